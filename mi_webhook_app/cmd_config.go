@@ -0,0 +1,47 @@
+// File: cmd_config.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"mi_webhook_app/src/application"
+)
+
+// runConfig agrupa los subcomandos de configuración. Hoy solo existe "validate".
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return fmt.Errorf("usage: config validate")
+	}
+	flags := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Las notificaciones se construyen en código (no hay plantillas externas que validar);
+	// se listan las rutas para confirmar qué eventos maneja este binario.
+	routes := application.RegisteredRoutes()
+	fmt.Printf("Registered routes (%d):\n", len(routes))
+	for _, route := range routes {
+		fmt.Printf("  %s\n", route)
+	}
+
+	problems := cfg.Validate()
+	if len(problems) > 0 {
+		fmt.Println("Configuration problems:")
+		messages := make([]string, len(problems))
+		for i, problem := range problems {
+			fmt.Printf("  - %v\n", problem)
+			messages[i] = problem.Error()
+		}
+		return fmt.Errorf("invalid configuration: %s", strings.Join(messages, "; "))
+	}
+	fmt.Println("Configuration OK")
+	return nil
+}
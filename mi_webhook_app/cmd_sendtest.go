@@ -0,0 +1,48 @@
+// File: cmd_sendtest.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/services"
+)
+
+// runSendTest publica un embed de prueba en un canal para comprobar su webhook.
+func runSendTest(args []string) error {
+	flags := flag.NewFlagSet("send-test", flag.ContinueOnError)
+	channel := flags.String("channel", "development", "logical channel to post to (development, testing)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	payload := application.DiscordPayload{Embeds: []application.DiscordEmbed{{
+		Title:       "🧪 Test notification",
+		Description: fmt.Sprintf("If you can read this, the `%s` webhook is configured correctly.", *channel),
+		Color:       3447003, // Azul
+		Footer:      &application.DiscordFooter{Text: fmt.Sprintf("Sent by send-test from %s", hostname)},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}}}
+
+	ctx := application.WithLogAttrs(context.Background(), "destination", *channel)
+	message, err := services.NewDiscordNotifier(cfg).SendNotification(ctx, *channel, payload)
+	if err != nil {
+		return err
+	}
+	if message != nil {
+		fmt.Printf("Test notification sent to %s (message %s)\n", *channel, message.ID)
+		return nil
+	}
+	fmt.Printf("Test notification sent to %s\n", *channel)
+	return nil
+}
@@ -0,0 +1,295 @@
+// File: cmd_serve.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/config"
+	"mi_webhook_app/src/infrastructure/github"
+	"mi_webhook_app/src/infrastructure/handlers"
+	"mi_webhook_app/src/infrastructure/metrics"
+	"mi_webhook_app/src/infrastructure/router"
+	"mi_webhook_app/src/infrastructure/scheduler"
+	"mi_webhook_app/src/infrastructure/services"
+	"mi_webhook_app/src/infrastructure/storage"
+	"mi_webhook_app/src/infrastructure/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runServe inicia el servidor HTTP de webhooks y lo apaga ordenadamente con SIGTERM/SIGINT.
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// 1. Load Configuration (Infrastructure)
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	// Las entradas inválidas (ej: un QUIET_HOURS mal escrito) se ignorarían en silencio: no se arranca
+	if problems := cfg.Validate(); len(problems) > 0 {
+		for _, problem := range problems {
+			slog.Error("Configuration problem", "error", problem)
+		}
+		return fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+
+	// Tracing: el exportador se vacía al final del apagado para no perder spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter)
+	if err != nil {
+		return fmt.Errorf("failed to configure tracing: %w", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("Flushing traces", "error", err)
+		}
+	}()
+
+	// 2. Initialize Driven Adapters (Infrastructure)
+	// Crea el adaptador concreto del notificador Discord
+	discordNotifier := services.NewDiscordNotifier(cfg)
+	// Con una ventana configurada, los envíos de un mismo PR o commit se agrupan en un solo mensaje
+	if cfg.CoalesceWindow > 0 {
+		coalescing := services.NewCoalescingNotifier(discordNotifier, cfg.CoalesceWindow)
+		defer func() {
+			flushCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			defer cancel()
+			if err := coalescing.Flush(flushCtx); err != nil {
+				slog.Warn("Flushing coalesced notifications", "error", err)
+			}
+		}()
+		discordNotifier = coalescing
+	}
+	// Almacenamiento embebido para el historial de entregas, los hilos de cada PR y los mensajes de estado
+	store, err := storage.OpenBoltStore(cfg.DatabasePath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// Cliente de la API de GitHub para enriquecer notificaciones (ej: archivos de un PR)
+	githubClient, err := newGitHubClient(cfg, store)
+	if err != nil {
+		return err
+	}
+
+	// 3. Initialize Application Service (Core)
+	// Crea el servicio de aplicación central, inyectando el adaptador notificador
+	// a través del puerto de interfaz application.NotificationService.
+	options := []application.ServiceOption{
+		application.WithDeliveryStore(store, cfg.StoreRawPayloads),
+		application.WithDeliveryRetention(cfg.DeliveryRetention),
+		application.WithDryRunMode(cfg.DryRun, cfg.DryRunDestinations...),
+		application.WithPullRequestThreads(store, cfg.PullRequestThreads...),
+		application.WithStatusMessages(store),
+		application.WithMentions(cfg.DiscordUserIDs, cfg.DiscordRoleIDs),
+		application.WithDeploymentEnvironments(cfg.DeployEnvironments...),
+	}
+	// Solo las entregas firmadas pueden cambiar qué repositorios cubre la GitHub App
+	if cfg.GithubWebhookSecret != "" {
+		options = append(options, application.WithInstallationStore(store))
+	}
+	codeOwners, err := codeOwnersOption(cfg, githubClient)
+	if err != nil {
+		return err
+	}
+	if codeOwners != nil {
+		options = append(options, codeOwners)
+	}
+	// Sin credenciales la API solo ve repositorios públicos y con un límite muy bajo
+	if cfg.GithubEnrichment && cfg.GithubAuthenticated() {
+		options = append(options,
+			application.WithGitHubEnrichment(githubClient),
+			application.WithJobLogExcerpts(cfg.WorkflowLogLines),
+		)
+	}
+	if cfg.BranchAlerts {
+		options = append(options, application.WithBranchHealth(store, cfg.SuppressRepeatedFailures))
+	}
+	if cfg.FlakyDetection {
+		options = append(options, application.WithFlakyDetection(store, cfg.FlakyWindow, cfg.FlakyThreshold))
+	}
+	if reviews := cfg.ReviewReminders; reviews.Enabled {
+		options = append(options, application.WithReviewReminders(store, application.ReviewReminderConfig{
+			Threshold:      reviews.Threshold,
+			RepoThresholds: reviews.RepoThresholds,
+			Calendar:       application.WorkingHours(reviews.WorkingHours),
+			RepoCalendars:  workingHoursByRepo(reviews.RepoWorkingHours),
+			SnoozeLabel:    reviews.SnoozeLabel,
+		}))
+	}
+	if len(cfg.QuietHours) > 0 {
+		options = append(options, application.WithQuietHours(store, quietHoursByKey(cfg.QuietHours)))
+	}
+	schedules := maps.Clone(cfg.ReportSchedules)
+	for _, digest := range cfg.Digests {
+		options = append(options, application.WithDigest(application.DigestConfig{
+			Destination: digest.Channel,
+			Period:      digest.Period,
+			Repos:       digest.Repos,
+			Metrics:     digest.Metrics,
+		}))
+		schedules[application.DigestReport(digest.Channel)] = digest.Schedule
+	}
+	webhookService := application.NewWebhookService(discordNotifier, options...)
+	// Métricas del ciclo de vida (tiempo a revisión, lead time, ...) en /metrics
+	prometheus.MustRegister(metrics.NewLifecycleCollector(webhookService, cfg.MetricsWindow))
+	if len(cfg.QuietHours) > 0 {
+		prometheus.MustRegister(metrics.NewHeldNotificationsCollector(store))
+	}
+
+	// Reportes periódicos (ej: workflows inestables de la semana, recordatorios de revisión, digests de cada canal)
+	reports, err := scheduler.New(webhookService, schedules)
+	if err != nil {
+		return err
+	}
+	reports.Start()
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := reports.Stop(stopCtx); err != nil {
+			slog.Warn("Stopping report scheduler", "error", err)
+		}
+	}()
+
+	// 4. Initialize Driving Adapters (Infrastructure)
+	gin.SetMode(gin.ReleaseMode) // O gin.DebugMode
+	engine := gin.New()
+	engine.Use(handlers.RequestLogger(), gin.Recovery())
+	readiness := handlers.NewReadiness()
+	// Configura rutas, inyectando el servicio de aplicación (webhookService)
+	// que cumple con el puerto application.WebhookProcessor.
+	router.SetupRoutes(engine, router.Dependencies{
+		Processor:     webhookService,
+		Readiness:     readiness,
+		Deliveries:    store,
+		Destinations:  config.Channels,
+		Metrics:       webhookService,
+		AdminToken:    cfg.AdminToken,
+		WebhookSecret: cfg.GithubWebhookSecret,
+	})
+
+	// 5. Start the Server (Infrastructure)
+	// baseCtx es el contexto de todas las peticiones; se cancela solo si el drenado excede el plazo.
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	server := &http.Server{
+		Addr:        ":" + cfg.Port,
+		Handler:     engine,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server starting", "port", cfg.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to run server: %w", err)
+		}
+		return nil
+	case <-signalCtx.Done():
+	}
+
+	// 6. Graceful Shutdown (Infrastructure)
+	// Deja de anunciarse como listo, deja de aceptar conexiones y espera a los handlers en curso.
+	slog.Info("Shutdown signal received, draining in-flight requests", "deadline", cfg.ShutdownTimeout.String())
+	readiness.SetReady(false)
+	// El balanceador necesita ver /ready fallando antes de que se cierren los listeners;
+	// mientras tanto se siguen atendiendo las peticiones que lleguen.
+	if cfg.ShutdownDrainDelay > 0 {
+		slog.Info("Waiting for load balancers to stop routing traffic", "delay", cfg.ShutdownDrainDelay.String())
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// Se agotó el plazo: cancela los envíos pendientes a Discord y cierra las conexiones restantes.
+		slog.Warn("Graceful shutdown did not finish in time", "error", err)
+		cancelRequests()
+		if err := server.Close(); err != nil {
+			slog.Error("Closing server", "error", err)
+		}
+	}
+	slog.Info("Server stopped")
+	return nil
+}
+
+// newGitHubClient crea el cliente de la API de GitHub. Con una GitHub App configurada cada
+// petición usa el token de la instalación que cubre el repositorio; si no, GITHUB_TOKEN.
+func newGitHubClient(cfg *config.AppConfig, installations application.InstallationStore) (*github.Client, error) {
+	if cfg.GithubAppID == 0 {
+		return github.NewClient(cfg.GithubAPIURL, cfg.GithubToken), nil
+	}
+	if cfg.GithubWebhookSecret == "" {
+		// Las instalaciones se registran con eventos del webhook: sin firma no son confiables
+		return nil, fmt.Errorf("GitHub App authentication requires GITHUB_WEBHOOK_SECRET")
+	}
+	client, err := github.NewAppClient(cfg.GithubAPIURL, cfg.GithubAppID, []byte(cfg.GithubAppPrivateKey), installations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitHub App authentication: %w", err)
+	}
+	slog.Info("Calling the GitHub API as a GitHub App", "app_id", cfg.GithubAppID)
+	return client, nil
+}
+
+// workingHoursByRepo convierte los calendarios por repositorio de la configuración.
+func workingHoursByRepo(calendars map[string]config.WorkingHours) map[string]application.WorkingHours {
+	converted := make(map[string]application.WorkingHours, len(calendars))
+	for repo, calendar := range calendars {
+		converted[repo] = application.WorkingHours(calendar)
+	}
+	return converted
+}
+
+// quietHoursByKey convierte los horarios de silencio de la configuración, indexados por destino o ruta.
+func quietHoursByKey(windows []config.QuietHours) map[string]application.QuietHours {
+	converted := make(map[string]application.QuietHours, len(windows))
+	for _, window := range windows {
+		converted[window.Key] = application.QuietHours{Start: window.Start, End: window.End, Location: window.Location, Drop: window.Drop}
+	}
+	return converted
+}
+
+// codeOwnersOption activa las menciones por CODEOWNERS si hay de dónde leerlo: un archivo
+// local (CODEOWNERS_FILE) o el de cada repositorio, si hay credenciales para la API de GitHub.
+// Retorna nil si la función queda deshabilitada.
+func codeOwnersOption(cfg *config.AppConfig, client *github.Client) (application.ServiceOption, error) {
+	switch {
+	case cfg.CodeOwnersFile != "":
+		source, err := services.LoadCodeOwnersFile(cfg.CodeOwnersFile)
+		if err != nil {
+			return nil, err
+		}
+		return application.WithCodeOwners(client, source), nil
+	case cfg.GithubAuthenticated():
+		return application.WithCodeOwners(client, github.NewCodeOwnersSource(client, cfg.CodeOwnersCacheTTL)), nil
+	default:
+		slog.Info("No CODEOWNERS_FILE or GitHub credentials configured, code owner mentions disabled")
+		return nil, nil
+	}
+}
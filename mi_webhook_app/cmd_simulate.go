@@ -0,0 +1,64 @@
+// File: cmd_simulate.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/services"
+)
+
+// runSimulate procesa un payload de archivo en dry-run e imprime las notificaciones resultantes.
+// No publica en Discord ni escribe en el historial.
+func runSimulate(args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	event := flags.String("event", "", "GitHub event name, as sent in X-GitHub-Event (e.g. pull_request)")
+	file := flags.String("file", "", "path to a JSON webhook payload")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *event == "" || *file == "" {
+		return errors.New("usage: simulate --event <name> --file <path>")
+	}
+
+	payload, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("failed to read payload file: %w", err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	service := application.NewWebhookService(services.NewDiscordNotifier(cfg),
+		application.WithMentions(cfg.DiscordUserIDs, cfg.DiscordRoleIDs))
+
+	ctx := application.WithLogAttrs(context.Background(), "delivery_id", "simulate", "event", *event)
+	ctx = application.WithoutDeliveryRecord(application.WithDryRun(ctx))
+	ctx, capture := application.WithNotificationCapture(ctx)
+	processingErr := service.Process(ctx, application.Event{Name: *event, DeliveryID: "simulate", Payload: payload})
+
+	route, handled := capture.Route()
+	result := map[string]any{
+		"handled":       handled,
+		"route":         route,
+		"notifications": capture.Records(),
+	}
+	if processingErr != nil && !errors.Is(processingErr, application.ErrEventNotHandled) {
+		result["error"] = processingErr.Error()
+	}
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	if result["error"] != nil {
+		return processingErr
+	}
+	return nil
+}
@@ -2,44 +2,84 @@
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
 
 	// --- IMPORTACIONES ACTUALIZADAS (usa tu nombre de módulo) ---
-	"mi_webhook_app/src/application"
 	"mi_webhook_app/src/infrastructure/config"
-	"mi_webhook_app/src/infrastructure/router"
-	"mi_webhook_app/src/infrastructure/services"
+	"mi_webhook_app/src/infrastructure/logging"
 	// --- FIN IMPORTACIONES ACTUALIZADAS ---
-
-	"github.com/gin-gonic/gin"
 )
 
+const usage = `Usage: mi_webhook_app <command> [flags]
+
+Commands:
+  serve                                   Start the webhook server (default)
+  config validate                         Check configuration and list registered routes
+  send-test --channel <name>              Post a sample embed to a Discord channel
+  simulate --event <name> --file <path>   Run a payload through the service and print the notifications
+`
+
 func main() {
-	// 1. Load Configuration (Infrastructure)
-	cfg, err := config.LoadConfig()
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	// 0. Structured Logging (Infrastructure)
+	// El servidor loguea a stdout; los comandos de CLI a stderr para no mezclar logs con su salida.
+	if command == "serve" {
+		logging.Init(os.Stdout)
+	} else {
+		logging.Init(os.Stderr)
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(args)
+	case "config":
+		err = runConfig(args)
+	case "send-test":
+		err = runSendTest(args)
+	case "simulate":
+		err = runSimulate(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("ERROR: Failed to load configuration: %v", err)
+		slog.Error("Command failed", "command", command, "error", err)
+		os.Exit(1)
 	}
+}
 
-	// 2. Initialize Driven Adapters (Infrastructure)
-	// Crea el adaptador concreto del notificador Discord
-	discordNotifier := services.NewDiscordNotifier(cfg)
-
-	// 3. Initialize Application Service (Core)
-	// Crea el servicio de aplicación central, inyectando el adaptador notificador
-	// a través del puerto de interfaz application.NotificationService.
-	webhookService := application.NewWebhookService(discordNotifier)
-
-	// 4. Initialize Driving Adapters (Infrastructure)
-	gin.SetMode(gin.ReleaseMode) // O gin.DebugMode
-	engine := gin.Default()
-	// Configura rutas, inyectando el servicio de aplicación (webhookService)
-	// que cumple con el puerto application.WebhookProcessor.
-	router.SetupRoutes(engine, webhookService)
-
-	// 5. Start the Server (Infrastructure)
-	log.Printf("INFO: Server starting on port %s", cfg.Port)
-	if err := engine.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("FATAL: Failed to run server: %v", err)
+// loadConfig carga la configuración y aplica lo que todos los comandos necesitan:
+// nivel de log y redacción de secretos.
+func loadConfig() (*config.AppConfig, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := logging.SetLevel(cfg.LogLevel); err != nil {
+		return nil, fmt.Errorf("failed to configure logging: %w", err)
 	}
+	// Las URLs de webhook incluyen el token: nunca deben aparecer en los logs
+	logging.RegisterSecret(cfg.DiscordWebhookURLDevelopment)
+	logging.RegisterSecret(cfg.DiscordWebhookURLTesting)
+	logging.RegisterSecret(cfg.AdminToken)
+	logging.RegisterSecret(cfg.GithubWebhookSecret)
+	logging.RegisterSecret(cfg.GithubToken)
+	logging.RegisterSecret(cfg.GithubAppPrivateKey)
+	return cfg, nil
 }
\ No newline at end of file
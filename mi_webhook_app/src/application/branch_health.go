@@ -0,0 +1,166 @@
+// File: src/application/branch_health.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// WithBranchHealth sigue el estado de cada workflow en la rama principal de cada repositorio
+// y publica un aviso cuando se rompe (🔥) y cuando se arregla (🩹). Con suppressRepeats, las
+// fallas siguientes de una rama que ya estaba rota no se notifican.
+func WithBranchHealth(store BranchStateStore, suppressRepeats bool) ServiceOption {
+	return func(s *webhookService) {
+		s.branchStates = store
+		s.suppressRepeatedFailures = suppressRepeats
+	}
+}
+
+// branchTransition es un cambio de estado de la rama pendiente de notificar.
+type branchTransition struct {
+	key     string
+	state   BranchState
+	payload *DiscordPayload // nil: solo se guarda el estado
+}
+
+// trackBranchHealth calcula el nuevo estado de la rama principal tras una ejecución completada.
+// Retorna nil si la ejecución no es de la rama principal o no cambia nada; repeated indica una
+// falla más de una rama que ya estaba rota.
+func (s *webhookService) trackBranchHealth(ctx context.Context, event *domain.WorkflowRunEventPayload) (transition *branchTransition, repeated bool) {
+	run := event.WorkflowRun
+	repo := event.Repository
+	if s.branchStates == nil || repo.DefaultBranch == "" || run.HeadBranch != repo.DefaultBranch {
+		return nil, false
+	}
+	if run.Conclusion != "success" && run.Conclusion != "failure" {
+		return nil, false // Cancelada u omitida: no dice nada sobre el código
+	}
+
+	key := fmt.Sprintf("%s/%s/%d", repo.FullName, run.HeadBranch, run.WorkflowID)
+	previous, found, err := s.branchStates.GetBranchState(ctx, key)
+	if err != nil {
+		Logger(ctx).Error("Reading branch state", "branch_key", key, "error", err)
+		return nil, false
+	}
+	if found && run.UpdatedAt.Before(previous.LastRunAt) {
+		Logger(ctx).Info("Ignoring out-of-order run for branch state", "branch_key", key, "run_id", run.ID)
+		return nil, false
+	}
+
+	failing := run.Conclusion == "failure"
+	switch {
+	case failing && found && previous.Failing:
+		previous.Failures++
+		previous.LastRunAt = run.UpdatedAt
+		return &branchTransition{key: key, state: previous}, true
+	case failing:
+		// Sin estado previo se asume que pasaba: el servicio no vio la ejecución anterior
+		state := BranchState{
+			Failing:     true,
+			Since:       run.UpdatedAt,
+			LastRunAt:   run.UpdatedAt,
+			FirstSHA:    run.HeadSha,
+			FirstRunURL: run.HTMLURL,
+			Author:      workflowRunActor(run).Login,
+			Failures:    1,
+		}
+		return &branchTransition{key: key, state: state, payload: s.branchBrokenPayload(event, state)}, false
+	case found && previous.Failing:
+		state := BranchState{Since: run.UpdatedAt, LastRunAt: run.UpdatedAt}
+		return &branchTransition{key: key, state: state, payload: branchFixedPayload(event, previous)}, false
+	default:
+		if !found {
+			previous.Since = run.UpdatedAt
+		}
+		previous.LastRunAt = run.UpdatedAt
+		return &branchTransition{key: key, state: previous}, false
+	}
+}
+
+// applyBranchTransition publica el aviso de la transición (si lo hay) y guarda el nuevo estado.
+// El estado se guarda después del aviso para que una entrega reintentada vuelva a avisar, y no
+// se guarda en dry-run: la falla real que siga a una vista previa debe avisar que se rompió.
+func (s *webhookService) applyBranchTransition(ctx context.Context, transition *branchTransition) error {
+	if transition == nil {
+		return nil
+	}
+	if transition.payload != nil {
+		if err := s.notify(ctx, workflowRunChannel, *transition.payload); err != nil {
+			return err
+		}
+		Logger(ctx).Info("Branch state changed", "branch_key", transition.key, "failing", transition.state.Failing)
+	}
+	if s.isDryRun(ctx, workflowRunChannel) {
+		return nil
+	}
+	if err := s.branchStates.SaveBranchState(ctx, transition.key, transition.state); err != nil {
+		Logger(ctx).Error("Saving branch state", "branch_key", transition.key, "error", err)
+	}
+	return nil
+}
+
+// branchBrokenPayload es el aviso "🔥 main is broken", que menciona a quien subió el commit.
+func (s *webhookService) branchBrokenPayload(event *domain.WorkflowRunEventPayload, state BranchState) *DiscordPayload {
+	run := event.WorkflowRun
+	branch := run.HeadBranch
+	description := fmt.Sprintf("**%s** started failing on `%s` with %s", event.Workflow.Name, branch, commitLink(event.Repository, run.HeadSha))
+	if run.HeadCommit != nil {
+		description += ": " + truncate(firstLine(run.HeadCommit.Message), 200)
+	}
+	fields := []DiscordField{
+		{Name: "Repository", Value: fmt.Sprintf("[%s](%s)", event.Repository.FullName, event.Repository.HTMLURL), Inline: true},
+		{Name: "Failing Run", Value: fmt.Sprintf("[#%d](%s)", run.RunNumber, run.HTMLURL), Inline: true},
+	}
+	if run.HeadCommit != nil && run.HeadCommit.Author.Name != "" {
+		fields = append(fields, DiscordField{Name: "Commit Author", Value: run.HeadCommit.Author.Name, Inline: true})
+	}
+	payload := &DiscordPayload{Embeds: []DiscordEmbed{{
+		Title:       fmt.Sprintf("🔥 %s is broken: %s", branch, event.Workflow.Name),
+		Description: description,
+		URL:         run.HTMLURL,
+		Color:       15158332, // Rojo
+		Fields:      fields,
+		Footer:      &DiscordFooter{Text: fmt.Sprintf("Workflow: %s", event.Workflow.Path)},
+		Timestamp:   run.UpdatedAt.Format(time.RFC3339),
+	}}}
+	payload.Critical = true // Un main roto se avisa aunque el destino esté en horario de silencio
+	var pings mentionList
+	s.mentionUser(&pings, state.Author)
+	pings.apply(payload, branch+" is broken")
+	return payload
+}
+
+// branchFixedPayload es el aviso "🩹 main fixed", con cuánto tiempo estuvo rota la rama.
+func branchFixedPayload(event *domain.WorkflowRunEventPayload, broken BranchState) *DiscordPayload {
+	run := event.WorkflowRun
+	branch := run.HeadBranch
+	duration := run.UpdatedAt.Sub(broken.Since).Round(time.Second)
+	return &DiscordPayload{Embeds: []DiscordEmbed{{
+		Title: fmt.Sprintf("🩹 %s fixed: %s", branch, event.Workflow.Name),
+		Description: fmt.Sprintf("**%s** is passing again on `%s` after being broken for **%s** (%d failed %s).",
+			event.Workflow.Name, branch, duration, broken.Failures, plural(broken.Failures, "run", "runs")),
+		URL:   run.HTMLURL,
+		Color: 3066993, // Verde
+		Fields: []DiscordField{
+			{Name: "Broken By", Value: fmt.Sprintf("%s ([run](%s))", commitLink(event.Repository, broken.FirstSHA), broken.FirstRunURL), Inline: true},
+			{Name: "Fixed By", Value: fmt.Sprintf("%s ([run](%s))", commitLink(event.Repository, run.HeadSha), run.HTMLURL), Inline: true},
+			{Name: "Time Broken", Value: duration.String(), Inline: true},
+		},
+		Footer:    &DiscordFooter{Text: fmt.Sprintf("Workflow: %s", event.Workflow.Path)},
+		Timestamp: run.UpdatedAt.Format(time.RFC3339),
+	}}}
+}
+
+// commitLink enlaza un commit por su SHA abreviado.
+func commitLink(repo domain.Repository, sha string) string {
+	return fmt.Sprintf("[`%s`](%s/commit/%s)", sha[:min(7, len(sha))], repo.HTMLURL, sha)
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,155 @@
+// File: src/application/coalescing.go
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// coalesceKeyContextKey es la clave de contexto de la clave de agrupación de la entrega en curso.
+type coalesceKeyContextKey struct{}
+
+// CoalesceKey retorna la clave con la que el notificador puede agrupar el envío con otros del mismo
+// pull request o commit (ej: "octo-org/hello-world#42" o "octo-org/hello-world@6dcb09b").
+// Retorna "" si el envío debe publicarse enseguida (ej: el servicio necesita el mensaje creado).
+func CoalesceKey(ctx context.Context) string {
+	key, _ := ctx.Value(coalesceKeyContextKey{}).(string)
+	return key
+}
+
+// WithCoalesceKey agrupa los envíos hechos con el contexto bajo key; "" los publica enseguida.
+func WithCoalesceKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, coalesceKeyContextKey{}, key)
+}
+
+// withoutCoalescing marca el envío como inmediato: el llamador usa el mensaje que retorna
+// (ej: para editarlo después o para guardar el hilo que abre).
+func withoutCoalescing(ctx context.Context) context.Context {
+	if CoalesceKey(ctx) == "" {
+		return ctx
+	}
+	return WithCoalesceKey(ctx, "")
+}
+
+// coalesceKey arma la clave de agrupación de una entrega: el pull request si lo hay (también el de
+// las ejecuciones de CI disparadas por él) o, si no, el commit. "" si la entrega no tiene ninguno.
+func coalesceKey(repo string, payload json.RawMessage) string {
+	var body struct {
+		PullRequest *struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+		WorkflowRun *struct {
+			HeadSHA      string `json:"head_sha"`
+			PullRequests []struct {
+				Number int `json:"number"`
+			} `json:"pull_requests"`
+		} `json:"workflow_run"`
+		CheckSuite *struct {
+			HeadSHA string `json:"head_sha"`
+		} `json:"check_suite"`
+	}
+	if repo == "" || json.Unmarshal(payload, &body) != nil {
+		return ""
+	}
+	switch {
+	case body.PullRequest != nil && body.PullRequest.Number > 0:
+		return pullRequestKey(repo, body.PullRequest.Number)
+	case body.WorkflowRun != nil && len(body.WorkflowRun.PullRequests) > 0:
+		return pullRequestKey(repo, body.WorkflowRun.PullRequests[0].Number)
+	case body.WorkflowRun != nil && body.WorkflowRun.HeadSHA != "":
+		return repo + "@" + body.WorkflowRun.HeadSHA
+	case body.CheckSuite != nil && body.CheckSuite.HeadSHA != "":
+		return repo + "@" + body.CheckSuite.HeadSHA
+	default:
+		return ""
+	}
+}
+
+// maxBatchEmbeds es el máximo de embeds que Discord acepta en un mensaje.
+const maxBatchEmbeds = 10
+
+// maxEmbedsLength es el máximo de caracteres que Discord acepta sumando todos los embeds de un
+// mensaje; un mensaje que lo supera se rechaza entero.
+const maxEmbedsLength = 6000
+
+// maxContentLength es el largo máximo que Discord acepta en el contenido de un mensaje.
+const maxContentLength = 2000
+
+// PayloadBatch es un mensaje que agrupa Count payloads consecutivos.
+type PayloadBatch struct {
+	Payload DiscordPayload
+	Count   int
+}
+
+// CoalescePayloads agrupa payloads consecutivos de un mismo destino e hilo en los menos mensajes
+// posibles, uniendo su contenido y sus menciones. Un payload se suma al mensaje anterior solo si
+// juntos respetan los límites de Discord (canMerge); si no, empieza otro mensaje. El contenido se
+// une por líneas completas, así que nunca se corta una mención. Los payloads con archivos
+// adjuntos se publican solos.
+func CoalescePayloads(payloads []DiscordPayload) []PayloadBatch {
+	var batches []PayloadBatch
+	for _, payload := range payloads {
+		if n := len(batches); n > 0 && canMerge(batches[n-1].Payload, payload) {
+			last := &batches[n-1]
+			last.Payload.Embeds = append(last.Payload.Embeds, payload.Embeds...)
+			last.Payload.Content = strings.TrimSpace(last.Payload.Content + "\n" + payload.Content)
+			last.Payload.AllowedMentions = mergeMentions(last.Payload.AllowedMentions, payload.AllowedMentions)
+			last.Payload.Critical = last.Payload.Critical || payload.Critical
+			last.Count++
+			continue
+		}
+		payload.Embeds = slices.Clone(payload.Embeds)
+		batches = append(batches, PayloadBatch{Payload: payload, Count: 1})
+	}
+	return batches
+}
+
+// canMerge indica si next cabe en el mismo mensaje que batch: sin adjuntos, hasta 10 embeds que
+// suman hasta 6000 caracteres y un contenido de hasta 2000.
+func canMerge(batch, next DiscordPayload) bool {
+	return len(batch.Files) == 0 && len(next.Files) == 0 &&
+		len(batch.Embeds)+len(next.Embeds) <= maxBatchEmbeds &&
+		embedsLength(batch.Embeds)+embedsLength(next.Embeds) <= maxEmbedsLength &&
+		len(batch.Content)+len(next.Content)+1 <= maxContentLength
+}
+
+// embedsLength cuenta los caracteres de los embeds como lo hace Discord para su límite:
+// título, descripción, nombre y valor de cada campo y texto del pie.
+func embedsLength(embeds []DiscordEmbed) int {
+	total := 0
+	for _, embed := range embeds {
+		total += utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+		for _, field := range embed.Fields {
+			total += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		}
+		if embed.Footer != nil {
+			total += utf8.RuneCountInString(embed.Footer.Text)
+		}
+	}
+	return total
+}
+
+// mergeMentions une las menciones autorizadas de dos payloads sin repetir IDs.
+func mergeMentions(a, b *AllowedMentions) *AllowedMentions {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	merged := &AllowedMentions{Parse: []string{}, Users: slices.Clone(a.Users), Roles: slices.Clone(a.Roles)}
+	for _, id := range b.Users {
+		if !slices.Contains(merged.Users, id) && len(merged.Users) < maxAllowedMentions {
+			merged.Users = append(merged.Users, id)
+		}
+	}
+	for _, id := range b.Roles {
+		if !slices.Contains(merged.Roles, id) && len(merged.Roles) < maxAllowedMentions {
+			merged.Roles = append(merged.Roles, id)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,76 @@
+// File: src/application/code_owners.go
+package application
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// WithCodeOwners menciona a los dueños (según CODEOWNERS) de los archivos modificados
+// cuando un pull request se abre o se marca listo para revisión.
+func WithCodeOwners(files ChangedFilesSource, owners CodeOwnersSource) ServiceOption {
+	return func(s *webhookService) {
+		s.changedFiles = files
+		s.codeOwners = owners
+	}
+}
+
+// resolveCodeOwners retorna los dueños de los archivos modificados por el PR, sin repetir y sin el autor.
+// Un fallo al consultar GitHub solo se registra: la notificación sale sin esos dueños.
+func (s *webhookService) resolveCodeOwners(ctx context.Context, repo string, number int, author string) []string {
+	if s.changedFiles == nil || s.codeOwners == nil {
+		return nil
+	}
+	ctx, span := startSpan(ctx, "codeowners.resolve")
+	defer span.End()
+
+	codeOwners, err := s.codeOwners.CodeOwners(ctx, repo)
+	if err != nil {
+		Logger(ctx).Warn("Loading CODEOWNERS failed, notifying without code owners", "error", err)
+		return nil
+	}
+	if codeOwners == nil {
+		return nil
+	}
+	files, err := s.changedFiles.ChangedFiles(ctx, repo, number)
+	if err != nil {
+		Logger(ctx).Warn("Listing changed files failed, notifying without code owners", "error", err)
+		return nil
+	}
+
+	var owners []string
+	for _, file := range files {
+		for _, owner := range codeOwners.Owners(file) {
+			if !slices.Contains(owners, owner) && !strings.EqualFold(owner, "@"+author) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	Logger(ctx).Debug("Resolved code owners", "files", len(files), "owners", len(owners))
+	return owners
+}
+
+// mentionOwners menciona a los dueños: "@org/equipo" como rol y "@usuario" como usuario.
+// Los dueños por email no tienen equivalente en Discord.
+func (s *webhookService) mentionOwners(pings *mentionList, owners []string) {
+	for _, owner := range owners {
+		name, ok := strings.CutPrefix(owner, "@")
+		if !ok {
+			continue
+		}
+		if org, team, isTeam := strings.Cut(name, "/"); isTeam {
+			s.mentionTeam(pings, org, team)
+			continue
+		}
+		s.mentionUser(pings, name)
+	}
+}
+
+// codeOwnerFields lista los dueños en el embed, tengan o no mención en Discord.
+func codeOwnerFields(owners []string) []DiscordField {
+	if len(owners) == 0 {
+		return nil
+	}
+	return []DiscordField{{Name: "Code Owners", Value: truncate(strings.Join(owners, ", "), maxFieldValueLength)}}
+}
@@ -0,0 +1,153 @@
+// File: src/application/delivery_history.go
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// ServiceOption configura dependencias opcionales de webhookService.
+type ServiceOption func(s *webhookService)
+
+// WithDeliveryStore registra cada entrega procesada en el historial.
+// Si storePayloads es true también se guarda el payload crudo.
+func WithDeliveryStore(store DeliveryStore, storePayloads bool) ServiceOption {
+	return func(s *webhookService) {
+		s.deliveries = store
+		s.storePayloads = storePayloads
+	}
+}
+
+// DeliveryRetentionReport es la tarea periódica que borra las entregas más antiguas que la retención.
+const DeliveryRetentionReport = "delivery_retention"
+
+// WithDeliveryRetention borra del historial las entregas con más de retention de antigüedad
+// (tarea DeliveryRetentionReport). Requiere WithDeliveryStore.
+func WithDeliveryRetention(retention time.Duration) ServiceOption {
+	return func(s *webhookService) {
+		s.registerReport(DeliveryRetentionReport, func(ctx context.Context) error {
+			if s.deliveries == nil {
+				return nil
+			}
+			pruned, err := s.deliveries.PruneDeliveries(ctx, time.Now().Add(-retention))
+			if err != nil {
+				return fmt.Errorf("failed to prune delivery history: %w", err)
+			}
+			if pruned > 0 {
+				Logger(ctx).Info("Pruned delivery history", "deliveries", pruned)
+			}
+			return nil
+		})
+	}
+}
+
+// saveDelivery guarda el resultado de la entrega. Un fallo del historial no hace fallar la entrega.
+// Las entregas en dry-run no se guardan: quien la pidió recibe los envíos capturados en la respuesta.
+func (s *webhookService) saveDelivery(ctx context.Context, record DeliveryRecord, payload []byte, processErr error, capture *NotificationCapture) {
+	if s.deliveries == nil || skipHistory(ctx) || IsDryRun(ctx) {
+		return
+	}
+	switch {
+	case processErr == nil:
+		record.Outcome = OutcomeProcessed
+	case errors.Is(processErr, ErrEventNotHandled):
+		record.Outcome = OutcomeIgnored
+	default:
+		record.Outcome = OutcomeError
+		record.Error = processErr.Error()
+	}
+	record.Notifications = capture.Records()
+	if s.storePayloads && json.Valid(payload) {
+		record.Payload = json.RawMessage(payload)
+	}
+	if record.ID == "" {
+		// Entregas sin X-GitHub-Delivery (ej: pruebas manuales) también quedan registradas
+		record.ID = "local-" + record.ReceivedAt.Format("20060102T150405.000000000")
+	}
+	if err := s.deliveries.SaveDelivery(ctx, record); err != nil {
+		Logger(ctx).Error("Saving delivery to history", "error", err)
+	}
+}
+
+// isDuplicate indica si el historial ya tiene la entrega (GitHub reintenta o se pide "Redeliver"
+// con el mismo X-GitHub-Delivery). Las que fallaron se procesan de nuevo; las reproducciones y los
+// dry-run, siempre. Dos copias simultáneas de una entrega pueden procesarse ambas.
+func (s *webhookService) isDuplicate(ctx context.Context, event Event) bool {
+	if s.deliveries == nil || event.DeliveryID == "" || event.ReplayOf != "" || skipHistory(ctx) || IsDryRun(ctx) {
+		return false
+	}
+	previous, err := s.deliveries.GetDelivery(ctx, event.DeliveryID)
+	if err != nil {
+		if !errors.Is(err, ErrDeliveryNotFound) {
+			Logger(ctx).Error("Reading delivery from history", "error", err)
+		}
+		return false
+	}
+	return previous.Outcome != OutcomeError
+}
+
+// newDeliveryRecord crea el registro base de una entrega recibida ahora.
+func newDeliveryRecord(event Event, repo, sender string) DeliveryRecord {
+	return DeliveryRecord{
+		ID:         event.DeliveryID,
+		Event:      event.Name,
+		Action:     event.Action,
+		Repo:       repo,
+		Sender:     sender,
+		ReceivedAt: time.Now().UTC(),
+		ReplayOf:   event.ReplayOf,
+		Summary:    summarizeDelivery(event),
+	}
+}
+
+// summarizeDelivery extrae del payload los datos que usan los digests y las métricas.
+// Retorna nil para eventos que no cubren o payloads ilegibles.
+func summarizeDelivery(event Event) *DeliverySummary {
+	switch event.Name {
+	case "pull_request", "pull_request_review":
+		var payload struct {
+			PullRequest domain.PullRequest `json:"pull_request"`
+			Review      domain.Review      `json:"review"`
+		}
+		if json.Unmarshal(event.Payload, &payload) != nil {
+			return nil
+		}
+		pr := payload.PullRequest
+		return &DeliverySummary{
+			Number: pr.Number, Title: pr.Title, URL: pr.HTMLURL, Author: pr.User.Login, Draft: pr.Draft, Merged: pr.Merged,
+			CreatedAt: pr.CreatedAt, MergedAt: pr.MergedAt, Additions: pr.Additions, Deletions: pr.Deletions, State: payload.Review.State,
+		}
+	case "deployment_status":
+		var payload domain.DeploymentStatusEventPayload
+		if json.Unmarshal(event.Payload, &payload) != nil {
+			return nil
+		}
+		status := payload.DeploymentStatus
+		environment := status.Environment
+		if environment == "" {
+			environment = payload.Deployment.Environment
+		}
+		return &DeliverySummary{State: status.State, Environment: environment, URL: status.TargetURL, Author: payload.Deployment.Creator.Login}
+	case "workflow_run":
+		var payload domain.WorkflowRunEventPayload
+		if json.Unmarshal(event.Payload, &payload) != nil {
+			return nil
+		}
+		run := payload.WorkflowRun
+		return &DeliverySummary{Workflow: payload.Workflow.Name, Conclusion: run.Conclusion, URL: run.HTMLURL, Author: workflowRunActor(run).Login}
+	case "release":
+		var payload domain.ReleaseEventPayload
+		if json.Unmarshal(event.Payload, &payload) != nil {
+			return nil
+		}
+		release := payload.Release
+		return &DeliverySummary{Title: release.Name, Tag: release.TagName, URL: release.HTMLURL, Author: release.Author.Login}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,267 @@
+// File: src/application/digests.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// digestReportPrefix antecede al destino en el nombre del reporte de cada digest.
+const digestReportPrefix = "digest-"
+
+// reviewLookback es hasta dónde se busca en el historial un pull request que sigue esperando
+// revisión: puede llevar más que el período del digest.
+const reviewLookback = 30 * 24 * time.Hour
+
+// digestPageSize es cuántas entregas se leen del historial por consulta.
+const digestPageSize = 500
+
+// maxDigestDeliveries limita cuántas entregas se leen para un digest.
+const maxDigestDeliveries = 50_000
+
+// maxDigestItems limita las listas de cada sección (PRs en espera, workflows que más fallan).
+const maxDigestItems = 5
+
+// DigestConfig configura el digest de un canal.
+type DigestConfig struct {
+	Destination string
+	Period      time.Duration // Período que resume cada digest (ej: 24h o 168h)
+	Repos       []string      // Repositorios incluidos ("owner/repo"); vacío = todos
+	Metrics     bool          // Añade las métricas del ciclo de vida (tiempo a revisión, lead time, ...)
+}
+
+// DigestReport retorna el nombre del reporte con el digest del destino.
+func DigestReport(destination string) string {
+	return digestReportPrefix + destination
+}
+
+// WithDigest habilita un digest periódico para un canal, armado con el historial de entregas.
+// Se puede usar una vez por canal; requiere WithDeliveryStore.
+func WithDigest(config DigestConfig) ServiceOption {
+	return func(s *webhookService) {
+		s.registerReport(DigestReport(config.Destination), func(ctx context.Context) error {
+			return s.publishDigest(ctx, config)
+		})
+	}
+}
+
+// publishDigest resume la actividad del último período y la publica en el canal del digest.
+func (s *webhookService) publishDigest(ctx context.Context, config DigestConfig) error {
+	if s.deliveries == nil {
+		return fmt.Errorf("digest for %s requires the delivery history", config.Destination)
+	}
+	now := time.Now().UTC()
+	records, err := s.historySince(ctx, now.Add(-max(config.Period, reviewLookback)), now)
+	if err != nil {
+		return err
+	}
+	if len(config.Repos) > 0 {
+		records = slices.DeleteFunc(records, func(record DeliveryRecord) bool {
+			return !slices.Contains(config.Repos, record.Repo)
+		})
+	}
+
+	embed := buildDigest(records, now.Add(-config.Period), now)
+	if config.Metrics {
+		if field := lifecycleDigestField(computeLifecycleMetrics(records, now.Add(-config.Period), now, s.deployEnvironments)); field != nil {
+			embed.Fields = append(embed.Fields, *field)
+		}
+	}
+	if len(embed.Fields) == 0 {
+		Logger(ctx).Info("No activity in digest period, digest not sent", "destination", config.Destination)
+		return nil
+	}
+	embed.Title = "📰 " + digestTitle(config.Period)
+	if len(config.Repos) > 0 {
+		embed.Footer = &DiscordFooter{Text: truncate("Repositories: "+strings.Join(config.Repos, ", "), 2048)}
+	}
+	return s.notify(ctx, config.Destination, DiscordPayload{Embeds: []DiscordEmbed{embed}})
+}
+
+// historySince lee las entregas recibidas desde since hasta until, de la más antigua a la más
+// reciente. Cada página sigue desde la última entrega de la anterior. Si hay más de
+// maxDigestDeliveries se descartan las más antiguas y se registra un aviso.
+func (s *webhookService) historySince(ctx context.Context, since, until time.Time) ([]DeliveryRecord, error) {
+	var records []DeliveryRecord
+	filter := DeliveryFilter{Since: since, Until: until}
+	for {
+		filter.Limit = min(digestPageSize, maxDigestDeliveries-len(records))
+		if filter.Limit == 0 {
+			// Se lee una entrega más solo para saber si quedaron afuera
+			filter.Limit = 1
+		}
+		page, err := s.deliveries.ListDeliveries(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read delivery history: %w", err)
+		}
+		if len(records) == maxDigestDeliveries {
+			if len(page) > 0 {
+				Logger(ctx).Warn("Delivery history truncated, older deliveries are not counted",
+					"limit", maxDigestDeliveries, "oldest_counted", records[len(records)-1].ReceivedAt)
+			}
+			break
+		}
+		records = append(records, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+		last := page[len(page)-1]
+		filter.Until, filter.BeforeID = last.ReceivedAt, last.ID
+	}
+	slices.Reverse(records) // El historial lista primero las más recientes
+	return records, nil
+}
+
+// buildDigest arma las secciones del digest con las entregas del historial (de la más antigua a
+// la más reciente). Solo cuentan las recibidas desde since, salvo los PRs que esperan revisión.
+// Las secciones sin datos se omiten.
+func buildDigest(records []DeliveryRecord, since, now time.Time) DiscordEmbed {
+	type workflowStats struct {
+		repo, name     string
+		passed, failed int
+	}
+	type waitingPR struct {
+		label, url string
+		since      time.Time
+	}
+	opened := make(map[string]bool)
+	var merged, releases []string
+	workflows := make(map[string]*workflowStats)
+	waiting := make(map[string]*waitingPR)
+
+	for _, record := range records {
+		summary := record.Summary
+		if summary == nil || record.ReplayOf != "" {
+			continue // Las reproducciones no son actividad nueva
+		}
+		inPeriod := !record.ReceivedAt.Before(since)
+		prKey := fmt.Sprintf("%s#%d", record.Repo, summary.Number)
+		prLink := fmt.Sprintf("[%s](%s) %s", prKey, summary.URL, truncate(summary.Title, 80))
+
+		switch record.Event + "/" + record.Action {
+		case "pull_request/opened", "pull_request/reopened", "pull_request/ready_for_review", "pull_request/review_requested":
+			if inPeriod && record.Action == "opened" {
+				opened[prKey] = true
+			}
+			if !summary.Draft && waiting[prKey] == nil {
+				waiting[prKey] = &waitingPR{label: prLink, url: summary.URL, since: record.ReceivedAt}
+			}
+		case "pull_request/converted_to_draft", "pull_request_review/submitted":
+			delete(waiting, prKey)
+		case "pull_request/closed":
+			delete(waiting, prKey)
+			if inPeriod && summary.Merged {
+				merged = append(merged, prLink)
+			}
+		case "workflow_run/completed":
+			if !inPeriod || (summary.Conclusion != "success" && summary.Conclusion != "failure") {
+				continue
+			}
+			key := record.Repo + "/" + summary.Workflow
+			if workflows[key] == nil {
+				workflows[key] = &workflowStats{repo: record.Repo, name: summary.Workflow}
+			}
+			if summary.Conclusion == "success" {
+				workflows[key].passed++
+			} else {
+				workflows[key].failed++
+			}
+		case "release/published":
+			if inPeriod {
+				releases = append(releases, fmt.Sprintf("[%s](%s) %s", summary.Tag, summary.URL, record.Repo))
+			}
+		}
+	}
+
+	embed := DiscordEmbed{
+		Description: fmt.Sprintf("Activity from <t:%d:f> to <t:%d:f>", since.Unix(), now.Unix()),
+		Color:       3447003, // Azul
+		Timestamp:   now.Format(time.RFC3339),
+	}
+	if len(opened) > 0 || len(merged) > 0 {
+		value := fmt.Sprintf("🆕 %d opened · 🔀 %d merged", len(opened), len(merged))
+		if len(merged) > 0 {
+			value += "\n" + strings.Join(merged, "\n")
+		}
+		embed.Fields = append(embed.Fields, DiscordField{Name: "Pull Requests", Value: listValue(strings.Split(value, "\n"))})
+	}
+
+	if len(waiting) > 0 {
+		prs := make([]*waitingPR, 0, len(waiting))
+		for _, pr := range waiting {
+			prs = append(prs, pr)
+		}
+		sort.Slice(prs, func(i, j int) bool { return prs[i].since.Before(prs[j].since) })
+		var lines []string
+		for _, pr := range prs[:min(len(prs), maxDigestItems)] {
+			lines = append(lines, fmt.Sprintf("%s — waiting %s", pr.label, formatAge(now.Sub(pr.since))))
+		}
+		embed.Fields = append(embed.Fields, DiscordField{Name: "Waiting Longest for Review", Value: listValue(lines)})
+	}
+
+	if len(workflows) > 0 {
+		stats := make([]*workflowStats, 0, len(workflows))
+		for _, workflow := range workflows {
+			stats = append(stats, workflow)
+		}
+		sort.Slice(stats, func(i, j int) bool {
+			if runs := stats[i].passed + stats[i].failed; runs != stats[j].passed+stats[j].failed {
+				return runs > stats[j].passed+stats[j].failed
+			}
+			return stats[i].repo+stats[i].name < stats[j].repo+stats[j].name
+		})
+		var passRates []string
+		for _, workflow := range stats {
+			runs := workflow.passed + workflow.failed
+			passRates = append(passRates, fmt.Sprintf("**%s** (%s) — %d%% (%d/%d)", workflow.name, workflow.repo, workflow.passed*100/runs, workflow.passed, runs))
+		}
+		embed.Fields = append(embed.Fields, DiscordField{Name: "CI Pass Rate", Value: listValue(passRates)})
+
+		sort.SliceStable(stats, func(i, j int) bool { return stats[i].failed > stats[j].failed })
+		var failing []string
+		for _, workflow := range stats[:min(len(stats), maxDigestItems)] {
+			if workflow.failed > 0 {
+				failing = append(failing, fmt.Sprintf("**%s** (%s) — %d %s", workflow.name, workflow.repo, workflow.failed, plural(workflow.failed, "failure", "failures")))
+			}
+		}
+		if len(failing) > 0 {
+			embed.Fields = append(embed.Fields, DiscordField{Name: "Top Failing Workflows", Value: listValue(failing)})
+		}
+	}
+
+	if len(releases) > 0 {
+		embed.Fields = append(embed.Fields, DiscordField{Name: "Releases", Value: listValue(releases)})
+	}
+	return embed
+}
+
+// digestTitle nombra el digest según su período.
+func digestTitle(period time.Duration) string {
+	switch period {
+	case 24 * time.Hour:
+		return "Daily Digest"
+	case 7 * 24 * time.Hour:
+		return "Weekly Digest"
+	default:
+		return fmt.Sprintf("Digest (last %s)", formatAge(period))
+	}
+}
+
+// formatAge muestra una duración larga en días y horas (ej: "3d 4h", "5h", "12m").
+func formatAge(d time.Duration) string {
+	days, hours := int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour)
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+}
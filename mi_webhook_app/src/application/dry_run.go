@@ -0,0 +1,47 @@
+// File: src/application/dry_run.go
+package application
+
+import "context"
+
+// dryRunKey es la clave de contexto que activa el modo dry-run para una entrega.
+type dryRunKey struct{}
+
+// WithDryRun hace que las notificaciones de esta entrega se rendericen y registren sin enviarse.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun indica si la entrega del contexto está en modo dry-run.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// WithDryRunMode activa el dry-run para todos los destinos (global) o solo para los indicados.
+func WithDryRunMode(global bool, destinations ...string) ServiceOption {
+	return func(s *webhookService) {
+		s.dryRunAll = global
+		s.dryRunDestinations = make(map[string]bool, len(destinations))
+		for _, destination := range destinations {
+			s.dryRunDestinations[destination] = true
+		}
+	}
+}
+
+// isDryRun decide si un envío a destination debe registrarse sin publicarse.
+func (s *webhookService) isDryRun(ctx context.Context, destination string) bool {
+	return s.dryRunAll || s.dryRunDestinations[destination] || IsDryRun(ctx)
+}
+
+// skipHistoryKey marca procesamientos que no son entregas reales (ej: vista previa).
+type skipHistoryKey struct{}
+
+// WithoutDeliveryRecord evita que el procesamiento quede registrado en el historial.
+func WithoutDeliveryRecord(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipHistoryKey{}, true)
+}
+
+func skipHistory(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipHistoryKey{}).(bool)
+	return skip
+}
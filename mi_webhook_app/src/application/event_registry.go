@@ -0,0 +1,94 @@
+// File: src/application/event_registry.go
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// AnyAction registra un manejador para todas las acciones de un evento.
+const AnyAction = "*"
+
+// EventHandlerFunc procesa un evento ya despachado por el registro.
+type EventHandlerFunc func(ctx context.Context, event Event) error
+
+// eventKey identifica un manejador por evento y acción.
+type eventKey struct {
+	event  string
+	action string
+}
+
+// EventRegistry asocia pares (evento, acción) con sus manejadores.
+type EventRegistry struct {
+	handlers map[eventKey]EventHandlerFunc
+}
+
+// NewEventRegistry crea un registro vacío.
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{handlers: make(map[eventKey]EventHandlerFunc)}
+}
+
+// Register asocia un manejador a un evento y acción. Usa AnyAction para cubrir todas las acciones.
+func (r *EventRegistry) Register(event, action string, handler EventHandlerFunc) {
+	r.handlers[eventKey{event: event, action: action}] = handler
+}
+
+// Lookup busca el manejador para la acción exacta y, si no existe, el de AnyAction.
+// También retorna la ruta elegida (ej: "pull_request/opened") para diagnósticos.
+func (r *EventRegistry) Lookup(event, action string) (string, EventHandlerFunc, bool) {
+	if handler, ok := r.handlers[eventKey{event: event, action: action}]; ok {
+		return event + "/" + action, handler, true
+	}
+	handler, ok := r.handlers[eventKey{event: event, action: AnyAction}]
+	if !ok {
+		return "", nil, false
+	}
+	return event + "/" + AnyAction, handler, true
+}
+
+// Routes retorna las rutas registradas (ej: "pull_request/opened") en orden alfabético.
+func (r *EventRegistry) Routes() []string {
+	routes := make([]string, 0, len(r.handlers))
+	for key := range r.handlers {
+		routes = append(routes, key.event+"/"+key.action)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// RegisteredRoutes retorna las rutas que registran los módulos de evento compilados en el binario.
+func RegisteredRoutes() []string {
+	s := &webhookService{registry: NewEventRegistry()}
+	for _, module := range eventModules {
+		module(s)
+	}
+	return s.registry.Routes()
+}
+
+// eventModules contiene las funciones que registran los manejadores de cada tipo de evento.
+// Cada archivo de evento se añade a sí mismo desde init(), así que agregar un evento
+// nuevo no requiere tocar el servicio ni el handler HTTP.
+var eventModules []func(s *webhookService)
+
+// registerEventModule añade un módulo de evento. Se llama desde init().
+func registerEventModule(module func(s *webhookService)) {
+	eventModules = append(eventModules, module)
+}
+
+// decodeEvent adapta un manejador tipado a EventHandlerFunc decodificando el payload.
+func decodeEvent[T any](handler func(ctx context.Context, event Event, payload *T) error) EventHandlerFunc {
+	return func(ctx context.Context, event Event) error {
+		var payload T
+		_, span := startSpan(ctx, "payload.decode", AttrEvent.String(event.Name))
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			Logger(ctx).Error("Unmarshalling event payload", "error", err)
+			err = fmt.Errorf("failed to unmarshal %s payload: %w", event.Name, err)
+			endSpan(span, err)
+			return err
+		}
+		span.End()
+		return handler(ctx, event, &payload)
+	}
+}
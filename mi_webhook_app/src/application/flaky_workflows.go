@@ -0,0 +1,213 @@
+// File: src/application/flaky_workflows.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// FlakyReport es el nombre del reporte semanal de workflows inestables.
+const FlakyReport = "flaky"
+
+// flakyReportPeriod es el período que cubre el reporte: se publica una vez por semana.
+const flakyReportPeriod = 7 * 24 * time.Hour
+
+// minFlakyCommits es cuántos commits debe tener un workflow en la ventana antes de
+// calificarlo: con menos, un solo re-run exitoso daría un flip rate enorme.
+const minFlakyCommits = 5
+
+// maxReportedWorkflows limita cuántos workflows lista el reporte.
+const maxReportedWorkflows = 10
+
+// WithFlakyDetection guarda el resultado de cada ejecución completada y marca como flaky
+// las ejecuciones que fallaron y luego pasaron en un re-run. Las fallas de un workflow cuyo
+// flip rate en window alcanza thresholdPercent llevan una nota, y habilita FlakyReport.
+func WithFlakyDetection(store RunHistoryStore, window time.Duration, thresholdPercent int) ServiceOption {
+	return func(s *webhookService) {
+		s.runHistory = store
+		s.flakyWindow = window
+		s.flakyThreshold = thresholdPercent
+		s.registerReport(FlakyReport, s.publishFlakyReport)
+	}
+}
+
+// workflowFlakiness resume cuántos commits de un workflow cambiaron de resultado sin cambiar el código.
+type workflowFlakiness struct {
+	Repo     string
+	Workflow string
+	Commits  int // Commits con al menos un resultado
+	Flaky    int // Commits que fallaron y pasaron
+}
+
+// flipRate es el porcentaje de commits flaky.
+func (f workflowFlakiness) flipRate() int {
+	if f.Commits == 0 {
+		return 0
+	}
+	return f.Flaky * 100 / f.Commits
+}
+
+// flakyFields guarda el resultado de la ejecución y retorna la nota de inestabilidad: en un
+// éxito, si la misma ejecución falló en un intento anterior; en una falla, si el workflow suele
+// fallar sin motivo. Las vistas previas y las reproducciones no se guardan en el historial.
+func (s *webhookService) flakyFields(ctx context.Context, event *domain.WorkflowRunEventPayload) []DiscordField {
+	run := event.WorkflowRun
+	if s.runHistory == nil || (run.Conclusion != "success" && run.Conclusion != "failure") {
+		return nil
+	}
+	result := RunResult{
+		Repo:        event.Repository.FullName,
+		WorkflowID:  run.WorkflowID,
+		Workflow:    event.Workflow.Name,
+		HeadSHA:     run.HeadSha,
+		RunID:       run.ID,
+		Attempt:     max(run.RunAttempt, 1),
+		Conclusion:  run.Conclusion,
+		CompletedAt: run.UpdatedAt,
+	}
+	persist := !IsDryRun(ctx) && !isReplay(ctx)
+	if persist {
+		if err := s.runHistory.SaveRunResult(ctx, result); err != nil {
+			Logger(ctx).Error("Saving workflow run result", "error", err)
+			return nil
+		}
+	}
+	// La ventana se mide desde la ejecución, no desde el reloj: así una entrega reproducida
+	// se califica igual que cuando llegó
+	results, err := s.runHistory.ListRunResults(ctx, RunFilter{Repo: result.Repo, WorkflowID: result.WorkflowID, Since: result.CompletedAt.Add(-s.flakyWindow)})
+	if err != nil {
+		Logger(ctx).Error("Reading workflow run history", "error", err)
+		return nil
+	}
+	if !persist {
+		// La nota se calcula como si se hubiera guardado
+		results = append(results, result)
+	}
+
+	if run.Conclusion == "success" {
+		// Solo un re-run de la misma ejecución: otra ejecución del mismo commit no lo es
+		if result.Attempt == 1 {
+			return nil
+		}
+		for _, earlier := range results {
+			if earlier.RunID == result.RunID && earlier.Attempt < result.Attempt && earlier.Conclusion == "failure" {
+				Logger(ctx).Info("Workflow run marked as flaky", "run_id", run.ID, "head_sha", run.HeadSha)
+				return []DiscordField{{Name: "🎲 Flaky", Value: fmt.Sprintf("This run failed earlier and passed on attempt %d", result.Attempt)}}
+			}
+		}
+		return nil
+	}
+
+	stats := flakiness(results)
+	if len(stats) == 0 || stats[0].Commits < minFlakyCommits || stats[0].flipRate() < s.flakyThreshold {
+		return nil
+	}
+	return []DiscordField{{Name: "🎲 Flakiness", Value: fmt.Sprintf("likely flaky (%d%% recent flip rate)", stats[0].flipRate()), Inline: true}}
+}
+
+// flakiness agrupa los resultados por workflow y cuenta sus commits flaky: los que tienen una
+// ejecución que falló y pasó en otro intento. Retorna los workflows de mayor a menor flip rate.
+func flakiness(results []RunResult) []workflowFlakiness {
+	type workflowKey struct {
+		repo string
+		id   int64
+	}
+	type runKey struct {
+		sha string
+		id  int64
+	}
+	type runOutcome struct{ failed, passed bool }
+
+	workflows := make(map[workflowKey]*workflowFlakiness)
+	runs := make(map[workflowKey]map[runKey]*runOutcome)
+	for _, result := range results {
+		key := workflowKey{repo: result.Repo, id: result.WorkflowID}
+		if workflows[key] == nil {
+			workflows[key] = &workflowFlakiness{Repo: result.Repo}
+			runs[key] = make(map[runKey]*runOutcome)
+		}
+		workflows[key].Workflow = result.Workflow // El más reciente, por si lo renombraron
+		run := runKey{sha: result.HeadSHA, id: result.RunID}
+		outcome := runs[key][run]
+		if outcome == nil {
+			outcome = &runOutcome{}
+			runs[key][run] = outcome
+		}
+		outcome.failed = outcome.failed || result.Conclusion == "failure"
+		outcome.passed = outcome.passed || result.Conclusion == "success"
+	}
+
+	stats := make([]workflowFlakiness, 0, len(workflows))
+	for key, workflow := range workflows {
+		commits, flaky := make(map[string]bool), make(map[string]bool)
+		for run, outcome := range runs[key] {
+			commits[run.sha] = true
+			if outcome.failed && outcome.passed {
+				flaky[run.sha] = true
+			}
+		}
+		workflow.Commits, workflow.Flaky = len(commits), len(flaky)
+		stats = append(stats, *workflow)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].flipRate() != stats[j].flipRate() {
+			return stats[i].flipRate() > stats[j].flipRate()
+		}
+		return stats[i].Repo+stats[i].Workflow < stats[j].Repo+stats[j].Workflow
+	})
+	return stats
+}
+
+// publishFlakyReport publica los workflows con commits flaky en la última semana y
+// descarta el historial que ya no entra en la ventana.
+func (s *webhookService) publishFlakyReport(ctx context.Context) error {
+	now := time.Now().UTC()
+	results, err := s.runHistory.ListRunResults(ctx, RunFilter{Since: now.Add(-flakyReportPeriod)})
+	if err != nil {
+		return err
+	}
+	var flaky []workflowFlakiness
+	for _, workflow := range flakiness(results) {
+		if workflow.Flaky > 0 {
+			flaky = append(flaky, workflow)
+		}
+	}
+
+	if len(flaky) == 0 {
+		Logger(ctx).Info("No flaky workflows this week, report not sent")
+	} else {
+		embed := DiscordEmbed{
+			Title:       "🎲 Weekly Flaky Workflow Report",
+			Description: fmt.Sprintf("%d %s failed and then passed on the same commit in the last 7 days.", len(flaky), plural(len(flaky), "workflow", "workflows")),
+			Color:       16776960, // Amarillo
+			Footer:      &DiscordFooter{Text: "Flip rate: share of commits whose runs both failed and passed"},
+			Timestamp:   now.Format(time.RFC3339),
+		}
+		for i, workflow := range flaky {
+			if i == maxReportedWorkflows {
+				embed.Fields = append(embed.Fields, DiscordField{Name: "…", Value: fmt.Sprintf("and %d more", len(flaky)-maxReportedWorkflows)})
+				break
+			}
+			embed.Fields = append(embed.Fields, DiscordField{
+				Name:  truncate(fmt.Sprintf("%s · %s", workflow.Repo, workflow.Workflow), 256),
+				Value: fmt.Sprintf("%d%% flip rate (%d of %d commits)", workflow.flipRate(), workflow.Flaky, workflow.Commits),
+			})
+		}
+		if err := s.notify(ctx, workflowRunChannel, DiscordPayload{Embeds: []DiscordEmbed{embed}}); err != nil {
+			return err
+		}
+	}
+
+	retention := max(s.flakyWindow, flakyReportPeriod)
+	pruned, err := s.runHistory.PruneRunResults(ctx, now.Add(-retention))
+	if err != nil {
+		Logger(ctx).Warn("Pruning workflow run history", "error", err)
+	} else if pruned > 0 {
+		Logger(ctx).Info("Pruned workflow run history", "results", pruned)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// File: src/application/github_enrichment.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// enrichmentTimeout limita cada consulta de enriquecimiento para no retrasar la notificación.
+const enrichmentTimeout = 5 * time.Second
+
+// maxListedItems limita cuántos jobs o checks se listan en un campo.
+const maxListedItems = 10
+
+// WithGitHubEnrichment completa las notificaciones con datos de la API de GitHub que el
+// payload no trae (jobs fallidos, tamaño del PR, check runs).
+func WithGitHubEnrichment(client GitHubClient) ServiceOption {
+	return func(s *webhookService) {
+		s.github = client
+	}
+}
+
+// enrich ejecuta una consulta con su propio timeout y span. Si falla, lo registra y
+// retorna false: el llamador omite el dato y la notificación sale igual.
+func enrich[T any](ctx context.Context, name string, query func(ctx context.Context) (T, error)) (T, bool) {
+	ctx, cancel := context.WithTimeout(ctx, enrichmentTimeout)
+	defer cancel()
+	ctx, span := startSpan(ctx, "enrich."+name)
+	result, err := query(ctx)
+	endSpan(span, err)
+	if err != nil {
+		Logger(ctx).Warn("GitHub enrichment failed, sending plain notification", "enrichment", name, "error", err)
+		var zero T
+		return zero, false
+	}
+	return result, true
+}
+
+// pullRequestStatsFields muestra el tamaño del PR. Los eventos pull_request lo traen en el
+// payload; en los demás (ej: revisiones) se consulta la API.
+func (s *webhookService) pullRequestStatsFields(ctx context.Context, repo string, pr domain.PullRequest) []DiscordField {
+	stats := &PullRequestStats{Commits: pr.Commits, Additions: pr.Additions, Deletions: pr.Deletions, ChangedFiles: pr.ChangedFiles}
+	if stats.ChangedFiles == 0 {
+		if s.github == nil {
+			return nil
+		}
+		var ok bool
+		stats, ok = enrich(ctx, "pull_request_stats", func(ctx context.Context) (*PullRequestStats, error) {
+			return s.github.PullRequestStats(ctx, repo, pr.Number)
+		})
+		if !ok || stats.ChangedFiles == 0 {
+			return nil
+		}
+	}
+	value := fmt.Sprintf("+%d / -%d in %d %s", stats.Additions, stats.Deletions, stats.ChangedFiles, plural(stats.ChangedFiles, "file", "files"))
+	if stats.Commits > 0 {
+		value += fmt.Sprintf(" (%d %s)", stats.Commits, plural(stats.Commits, "commit", "commits"))
+	}
+	return []DiscordField{{Name: "Changes", Value: value, Inline: true}}
+}
+
+// failureDetails lista los jobs fallidos de una ejecución y el primer paso que falló en cada
+// uno y, si está habilitado, añade el extracto del log del primer job fallido y lo adjunta.
+func (s *webhookService) failureDetails(ctx context.Context, repo string, runID int64) ([]DiscordField, []DiscordFile) {
+	if s.github == nil {
+		return nil, nil
+	}
+	jobs, ok := enrich(ctx, "workflow_jobs", func(ctx context.Context) ([]WorkflowJob, error) {
+		return s.github.WorkflowRunJobs(ctx, repo, runID)
+	})
+	failed := failedJobs(jobs)
+	if !ok || len(failed) == 0 {
+		return nil, nil
+	}
+	var lines []string
+	for _, job := range failed {
+		line := fmt.Sprintf("[%s](%s)", job.Name, job.HTMLURL)
+		if step, found := firstFailedStep(job); found {
+			line += fmt.Sprintf(" › `%s`", step.Name)
+		}
+		lines = append(lines, line)
+	}
+	fields := []DiscordField{{Name: "Failed Jobs", Value: listValue(lines)}}
+	logFields, files := s.jobLogDetails(ctx, repo, failed[0])
+	return append(fields, logFields...), files
+}
+
+// checkRunsFields resume los check runs de un commit y nombra los que fallaron.
+func (s *webhookService) checkRunsFields(ctx context.Context, repo, sha string) []DiscordField {
+	if s.github == nil || sha == "" {
+		return nil
+	}
+	checks, ok := enrich(ctx, "check_runs", func(ctx context.Context) ([]CheckRun, error) {
+		return s.github.CheckRuns(ctx, repo, sha)
+	})
+	if !ok || len(checks) == 0 {
+		return nil
+	}
+	var passed, pending int
+	var failed []string
+	for _, check := range checks {
+		switch {
+		case check.Status != "completed":
+			pending++
+		case isFailedConclusion(check.Conclusion):
+			failed = append(failed, fmt.Sprintf("[%s](%s)", check.Name, check.HTMLURL))
+		default:
+			passed++ // success, neutral y skipped no bloquean
+		}
+	}
+	value := fmt.Sprintf("✅ %d passed · ❌ %d failed · ⏳ %d pending", passed, len(failed), pending)
+	if len(failed) > 0 {
+		value += "\n" + listValue(failed)
+	}
+	return []DiscordField{{Name: "Checks", Value: truncate(value, maxFieldValueLength)}}
+}
+
+// failedJobs retorna los jobs que fallaron (incluye los que agotaron su tiempo).
+func failedJobs(jobs []WorkflowJob) []WorkflowJob {
+	var failed []WorkflowJob
+	for _, job := range jobs {
+		if isFailedConclusion(job.Conclusion) {
+			failed = append(failed, job)
+		}
+	}
+	return failed
+}
+
+// firstFailedStep retorna el primer paso fallido de un job.
+func firstFailedStep(job WorkflowJob) (WorkflowStep, bool) {
+	for _, step := range job.Steps {
+		if isFailedConclusion(step.Conclusion) {
+			return step, true
+		}
+	}
+	return WorkflowStep{}, false
+}
+
+func isFailedConclusion(conclusion string) bool {
+	return conclusion == "failure" || conclusion == "timed_out"
+}
+
+// listValue une líneas para un campo, recortando la lista a maxListedItems.
+func listValue(lines []string) string {
+	if len(lines) > maxListedItems {
+		lines = append(lines[:maxListedItems:maxListedItems], fmt.Sprintf("…and %d more", len(lines)-maxListedItems))
+	}
+	return truncate(strings.Join(lines, "\n"), maxFieldValueLength)
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,108 @@
+// File: src/application/installation_events.go
+package application
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+func init() {
+	registerEventModule(registerInstallationHandlers)
+}
+
+// registerInstallationHandlers registra los eventos de la GitHub App. No notifican a Discord:
+// mantienen el registro de instalaciones con el que se eligen los tokens de la API.
+func registerInstallationHandlers(s *webhookService) {
+	s.registry.Register("installation", AnyAction, decodeEvent(s.handleInstallation))
+	s.registry.Register("installation_repositories", AnyAction, decodeEvent(s.handleInstallationRepositories))
+}
+
+// handleInstallation registra, actualiza o elimina una instalación.
+func (s *webhookService) handleInstallation(ctx context.Context, _ Event, event *domain.InstallationEventPayload) error {
+	if s.installations == nil {
+		Logger(ctx).Debug("No installation store configured, installation event ignored")
+		return nil
+	}
+	id := event.Installation.ID
+
+	switch event.Action {
+	case "deleted":
+		Logger(ctx).Info("GitHub App uninstalled", "installation_id", id, "account", event.Installation.Account.Login)
+		if IsDryRun(ctx) {
+			return nil
+		}
+		return s.installations.DeleteInstallation(ctx, id)
+	case "created", "new_permissions_accepted", "suspend", "unsuspend":
+	default:
+		Logger(ctx).Info("Unhandled installation action", "installation_id", id)
+		return nil
+	}
+
+	installation := Installation{
+		ID:                  id,
+		Account:             event.Installation.Account.Login,
+		RepositorySelection: event.Installation.RepositorySelection,
+		Suspended:           event.Action == "suspend",
+		UpdatedAt:           time.Now().UTC(),
+	}
+	if event.Action == "created" {
+		installation.Repositories = repositoryNames(event.Repositories)
+	} else if existing, found, err := s.installations.GetInstallation(ctx, id); err != nil {
+		return err
+	} else if found {
+		installation.Repositories = existing.Repositories // Estos eventos no traen la lista
+	}
+	Logger(ctx).Info("GitHub App installation updated", "installation_id", id, "account", installation.Account,
+		"repositories", len(installation.Repositories), "suspended", installation.Suspended)
+	if IsDryRun(ctx) {
+		return nil // Una vista previa no cambia qué repositorios cubre la App
+	}
+	return s.installations.SaveInstallation(ctx, installation)
+}
+
+// handleInstallationRepositories actualiza los repositorios que cubre una instalación.
+func (s *webhookService) handleInstallationRepositories(ctx context.Context, _ Event, event *domain.InstallationRepositoriesEventPayload) error {
+	if s.installations == nil {
+		Logger(ctx).Debug("No installation store configured, installation event ignored")
+		return nil
+	}
+	id := event.Installation.ID
+	installation, found, err := s.installations.GetInstallation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		// Instalada antes de que el servicio la registrara: se reconstruye desde este evento
+		installation = &Installation{ID: id, Account: event.Installation.Account.Login}
+	}
+
+	installation.RepositorySelection = event.RepositorySelection
+	for _, repo := range repositoryNames(event.RepositoriesAdded) {
+		if !slices.Contains(installation.Repositories, repo) {
+			installation.Repositories = append(installation.Repositories, repo)
+		}
+	}
+	removed := repositoryNames(event.RepositoriesRemoved)
+	installation.Repositories = slices.DeleteFunc(installation.Repositories, func(repo string) bool {
+		return slices.Contains(removed, repo)
+	})
+	installation.UpdatedAt = time.Now().UTC()
+
+	Logger(ctx).Info("GitHub App repositories updated", "installation_id", id,
+		"added", len(event.RepositoriesAdded), "removed", len(removed), "repositories", len(installation.Repositories))
+	if IsDryRun(ctx) {
+		return nil
+	}
+	return s.installations.SaveInstallation(ctx, *installation)
+}
+
+func repositoryNames(repositories []domain.InstallationRepository) []string {
+	names := make([]string, 0, len(repositories))
+	for _, repo := range repositories {
+		names = append(names, repo.FullName)
+	}
+	return names
+}
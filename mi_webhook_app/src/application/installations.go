@@ -0,0 +1,25 @@
+// File: src/application/installations.go
+package application
+
+import "context"
+
+// installationKey es la clave de contexto con el ID de instalación de la GitHub App de la entrega.
+type installationKey struct{}
+
+// WithInstallationID guarda en el contexto la instalación que envió la entrega.
+func WithInstallationID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, installationKey{}, id)
+}
+
+// InstallationID retorna la instalación de la entrega en curso, si el payload la traía.
+func InstallationID(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(installationKey{}).(int64)
+	return id, ok
+}
+
+// WithInstallationStore registra las instalaciones de la GitHub App y los repositorios que cubren.
+func WithInstallationStore(store InstallationStore) ServiceOption {
+	return func(s *webhookService) {
+		s.installations = store
+	}
+}
@@ -0,0 +1,126 @@
+// File: src/application/job_logs.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// logErrorMarker es la anotación con la que GitHub Actions marca los errores en un log.
+const logErrorMarker = "##[error]"
+
+// maxLinesAfterMarker es cuántas líneas posteriores al primer error se incluyen como máximo
+// en el extracto (un cuarto de él si es corto); el resto muestran lo que llevó al error.
+const maxLinesAfterMarker = 3
+
+// maxLogLineLength recorta las líneas muy largas (ej: comandos con muchas variables).
+const maxLogLineLength = 200
+
+// deliveryBudget es el tiempo que puede tomar una entrega: GitHub da por fallida la que no
+// responde en 10 s y la reenvía, con lo que la notificación se publicaría dos veces.
+const deliveryBudget = 8 * time.Second
+
+// logUploadAllowance es el tiempo que se reserva para publicar el adjunto tras descargar el log.
+const logUploadAllowance = 3 * time.Second
+
+// minLogDownloadTime es el tiempo mínimo para intentar la descarga; con menos se omite el log.
+const minLogDownloadTime = time.Second
+
+// logTimestamp es la marca de tiempo con la que empieza cada línea de un log de Actions.
+var logTimestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+
+// WithJobLogExcerpts añade a las ejecuciones fallidas un extracto de lines líneas del log del
+// primer job fallido y adjunta el log completo. Requiere WithGitHubEnrichment; 0 lo deshabilita.
+func WithJobLogExcerpts(lines int) ServiceOption {
+	return func(s *webhookService) {
+		s.jobLogLines = lines
+	}
+}
+
+// jobLogDetails descarga el log de job y retorna el campo con el extracto y el adjunto. Si a la
+// entrega no le queda tiempo para descargarlo y publicarlo, la notificación sale sin el log.
+func (s *webhookService) jobLogDetails(ctx context.Context, repo string, job WorkflowJob) ([]DiscordField, []DiscordFile) {
+	if s.github == nil || s.jobLogLines <= 0 {
+		return nil, nil
+	}
+	if left, ok := deliveryTimeLeft(ctx); ok {
+		if left-logUploadAllowance < minLogDownloadTime {
+			Logger(ctx).Info("Skipping job log: delivery deadline too close", "job_id", job.ID, "time_left", left.String())
+			return nil, nil
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, left-logUploadAllowance)
+		defer cancel()
+	}
+	log, ok := enrich(ctx, "job_logs", func(ctx context.Context) ([]byte, error) {
+		return s.github.JobLogs(ctx, repo, job.ID)
+	})
+	if !ok || len(log) == 0 {
+		return nil, nil
+	}
+	file := DiscordFile{Name: logFileName(job.Name), Content: log}
+	excerpt := logExcerpt(string(log), s.jobLogLines)
+	if excerpt == "" {
+		return nil, []DiscordFile{file}
+	}
+	field := DiscordField{Name: fmt.Sprintf("Log Excerpt (%s)", truncate(job.Name, 200)), Value: excerpt}
+	return []DiscordField{field}, []DiscordFile{file}
+}
+
+// logExcerpt toma hasta lines líneas alrededor del primer error del log (o las últimas, si no
+// hay ninguno) y las formatea como bloque de código que cabe en un campo de Discord.
+func logExcerpt(log string, lines int) string {
+	all := strings.Split(strings.TrimRight(strings.ReplaceAll(log, "\r\n", "\n"), "\n"), "\n")
+	end := len(all)
+	for i, line := range all {
+		if strings.Contains(line, logErrorMarker) {
+			end = min(i+1+min(maxLinesAfterMarker, lines/4), len(all))
+			break
+		}
+	}
+	excerpt := all[max(end-lines, 0):end]
+
+	cleaned := make([]string, 0, len(excerpt))
+	for _, line := range excerpt {
+		line = logTimestamp.ReplaceAllString(line, "")
+		// Un ``` dentro del log cerraría el bloque de código
+		line = strings.ReplaceAll(line, "```", "`\u200b``")
+		cleaned = append(cleaned, truncate(line, maxLogLineLength))
+	}
+	// Si no cabe, se descartan las primeras líneas: las cercanas al error son las útiles
+	for len(cleaned) > 0 {
+		value := "```\n" + strings.Join(cleaned, "\n") + "\n```"
+		if len(value) <= maxFieldValueLength {
+			if strings.TrimSpace(strings.Join(cleaned, "")) == "" {
+				return ""
+			}
+			return value
+		}
+		cleaned = cleaned[1:]
+	}
+	return ""
+}
+
+// logFileName arma el nombre del adjunto a partir del nombre del job.
+func logFileName(job string) string {
+	var name strings.Builder
+	for _, r := range job {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+			name.WriteRune(r)
+		case !strings.HasSuffix(name.String(), "-"):
+			name.WriteRune('-') // Espacios, paréntesis, etc. se agrupan en un solo guion
+		}
+	}
+	base := strings.Trim(name.String(), "-.")
+	if base == "" {
+		base = "job"
+	}
+	if len(base) > 80 {
+		base = base[:80]
+	}
+	return base + ".log"
+}
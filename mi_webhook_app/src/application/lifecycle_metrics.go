@@ -0,0 +1,201 @@
+// File: src/application/lifecycle_metrics.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultMetricsWindow es el período de las métricas cuando el filtro no lo indica.
+const defaultMetricsWindow = 30 * 24 * time.Hour
+
+// WithDeploymentEnvironments limita las métricas de deployments a los entornos indicados
+// (ej: "production"). Sin entornos cuentan todos.
+func WithDeploymentEnvironments(environments ...string) ServiceOption {
+	return func(s *webhookService) {
+		s.deployEnvironments = environments
+	}
+}
+
+// LifecycleMetrics implementa LifecycleMetricsSource. Requiere WithDeliveryStore: las métricas
+// se calculan con las entregas de pull_request, pull_request_review y deployment_status.
+func (s *webhookService) LifecycleMetrics(ctx context.Context, filter MetricsFilter) (LifecycleMetrics, error) {
+	if s.deliveries == nil {
+		return LifecycleMetrics{}, fmt.Errorf("lifecycle metrics require the delivery history")
+	}
+	if filter.Until.IsZero() {
+		filter.Until = time.Now().UTC()
+	}
+	if filter.Since.IsZero() {
+		filter.Since = filter.Until.Add(-defaultMetricsWindow)
+	}
+	// Las revisiones y los merges de los PRs desplegados en el período pueden ser anteriores
+	records, err := s.historySince(ctx, filter.Since.Add(-reviewLookback), filter.Until)
+	if err != nil {
+		return LifecycleMetrics{}, err
+	}
+	if filter.Repo != "" {
+		records = slices.DeleteFunc(records, func(record DeliveryRecord) bool { return record.Repo != filter.Repo })
+	}
+	metrics := computeLifecycleMetrics(records, filter.Since, filter.Until, s.deployEnvironments)
+	metrics.Repo = filter.Repo
+	return metrics, nil
+}
+
+// computeLifecycleMetrics calcula las métricas del período [since, until) con las entregas del
+// historial (de la más antigua a la más reciente). Los pull requests cuentan por su fecha de merge
+// y los deployments por la de su estado final.
+func computeLifecycleMetrics(records []DeliveryRecord, since, until time.Time, environments []string) LifecycleMetrics {
+	type reviewState struct {
+		firstReview      time.Time
+		changesRequested int
+	}
+	type mergedPR struct {
+		repo            string
+		created, merged time.Time
+		size            int
+		review          *reviewState
+	}
+	reviews := make(map[string]*reviewState)
+	var merged []mergedPR
+	deploys := make(map[string][]time.Time) // Deployments exitosos por repositorio
+	inPeriod := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+
+	metrics := LifecycleMetrics{Since: since, Until: until}
+	metrics.Deployments.Environments = environments
+	for _, record := range records {
+		summary := record.Summary
+		if summary == nil || record.ReplayOf != "" {
+			continue
+		}
+		prKey := fmt.Sprintf("%s#%d", record.Repo, summary.Number)
+		switch record.Event + "/" + record.Action {
+		case "pull_request_review/submitted":
+			if record.Sender == summary.Author {
+				continue // Los comentarios del autor en su propio PR no son una revisión
+			}
+			review := reviews[prKey]
+			if review == nil {
+				review = &reviewState{firstReview: record.ReceivedAt}
+				reviews[prKey] = review
+			}
+			if summary.State == "changes_requested" {
+				review.changesRequested++
+			}
+		case "pull_request/closed":
+			if !summary.Merged {
+				continue
+			}
+			pr := mergedPR{repo: record.Repo, merged: record.ReceivedAt, size: summary.Additions + summary.Deletions, review: reviews[prKey]}
+			if summary.MergedAt != nil {
+				pr.merged = *summary.MergedAt
+			}
+			pr.created = pr.merged
+			if summary.CreatedAt != nil {
+				pr.created = *summary.CreatedAt
+			}
+			merged = append(merged, pr)
+		case "deployment_status/created":
+			if len(environments) > 0 && !slices.ContainsFunc(environments, func(env string) bool {
+				return strings.EqualFold(env, summary.Environment)
+			}) {
+				continue
+			}
+			switch summary.State {
+			case "success":
+				deploys[record.Repo] = append(deploys[record.Repo], record.ReceivedAt)
+				if inPeriod(record.ReceivedAt) {
+					metrics.Deployments.Successful++
+				}
+			case "failure", "error":
+				if inPeriod(record.ReceivedAt) {
+					metrics.Deployments.Failed++
+				}
+			}
+		}
+	}
+
+	var firstReview, toMerge, iterations, size, leadTime []float64
+	for _, pr := range merged {
+		// Un PR entra en el primer deployment exitoso de su repositorio posterior al merge
+		repoDeploys := deploys[pr.repo]
+		if i := sort.Search(len(repoDeploys), func(i int) bool { return !repoDeploys[i].Before(pr.merged) }); i < len(repoDeploys) && inPeriod(repoDeploys[i]) {
+			leadTime = append(leadTime, repoDeploys[i].Sub(pr.created).Seconds())
+		}
+		if !inPeriod(pr.merged) {
+			continue
+		}
+		metrics.PullRequests.Merged++
+		toMerge = append(toMerge, pr.merged.Sub(pr.created).Seconds())
+		size = append(size, float64(pr.size))
+		if pr.review == nil {
+			iterations = append(iterations, 0)
+			continue
+		}
+		iterations = append(iterations, float64(1+pr.review.changesRequested))
+		firstReview = append(firstReview, pr.review.firstReview.Sub(pr.created).Seconds())
+	}
+	metrics.PullRequests.TimeToFirstReview = newDistribution(firstReview)
+	metrics.PullRequests.TimeToMerge = newDistribution(toMerge)
+	metrics.PullRequests.ReviewIterations = newDistribution(iterations)
+	metrics.PullRequests.Size = newDistribution(size)
+
+	deployments := &metrics.Deployments
+	deployments.LeadTime = newDistribution(leadTime)
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		deployments.PerDay = float64(deployments.Successful) / days
+	}
+	if finished := deployments.Successful + deployments.Failed; finished > 0 {
+		deployments.ChangeFailureRate = float64(deployments.Failed) / float64(finished)
+	}
+	return metrics
+}
+
+// newDistribution resume las muestras con su media, su mediana y su percentil 90 (por rango más cercano).
+func newDistribution(samples []float64) Distribution {
+	if len(samples) == 0 {
+		return Distribution{}
+	}
+	sorted := slices.Sorted(slices.Values(samples))
+	var sum float64
+	for _, sample := range sorted {
+		sum += sample
+	}
+	rank := func(p float64) float64 {
+		return sorted[max(int(math.Ceil(p*float64(len(sorted))))-1, 0)]
+	}
+	return Distribution{Count: len(sorted), Mean: sum / float64(len(sorted)), Median: rank(0.5), P90: rank(0.9)}
+}
+
+// lifecycleDigestField resume las métricas en un campo del digest. Retorna nil si no hubo merges ni deployments.
+func lifecycleDigestField(metrics LifecycleMetrics) *DiscordField {
+	prs, deployments := metrics.PullRequests, metrics.Deployments
+	var lines []string
+	if prs.Merged > 0 {
+		line := fmt.Sprintf("🔀 Time to merge: %s", formatAge(seconds(prs.TimeToMerge.Median)))
+		if prs.TimeToFirstReview.Count > 0 {
+			line = fmt.Sprintf("⏱ First review: %s · %s", formatAge(seconds(prs.TimeToFirstReview.Median)), line)
+		}
+		lines = append(lines, line+fmt.Sprintf(" · 📏 %d lines", int(prs.Size.Median)))
+	}
+	if finished := deployments.Successful + deployments.Failed; finished > 0 {
+		line := fmt.Sprintf("🚀 %d %s (%.1f/day)", deployments.Successful, plural(deployments.Successful, "deploy", "deploys"), deployments.PerDay)
+		if deployments.LeadTime.Count > 0 {
+			line += fmt.Sprintf(" · Lead time: %s", formatAge(seconds(deployments.LeadTime.Median)))
+		}
+		lines = append(lines, line+fmt.Sprintf(" · Change failure rate: %d%%", int(math.Round(deployments.ChangeFailureRate*100))))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return &DiscordField{Name: "Delivery Metrics (median)", Value: listValue(lines)}
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,26 @@
+// File: src/application/log_context.go
+package application
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logAttrsKey es la clave de contexto para los atributos de correlación de logs.
+type logAttrsKey struct{}
+
+// WithLogAttrs añade atributos (ej: "delivery_id", id) que acompañarán a cada línea de log
+// emitida con Logger(ctx) durante el procesamiento de una entrega.
+func WithLogAttrs(ctx context.Context, args ...any) context.Context {
+	current, _ := ctx.Value(logAttrsKey{}).([]any)
+	attrs := make([]any, 0, len(current)+len(args))
+	attrs = append(attrs, current...)
+	attrs = append(attrs, args...)
+	return context.WithValue(ctx, logAttrsKey{}, attrs)
+}
+
+// Logger retorna el logger por defecto con los atributos de correlación del contexto.
+func Logger(ctx context.Context) *slog.Logger {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]any)
+	return slog.Default().With(attrs...)
+}
@@ -0,0 +1,93 @@
+// File: src/application/mentions.go
+package application
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxAllowedMentions es el máximo de usuarios (y de roles) que Discord acepta en allowed_mentions.
+const maxAllowedMentions = 100
+
+// WithMentions configura el mapeo de logins de GitHub → IDs de usuario de Discord y de
+// equipos de GitHub ("org/slug" o "slug") → IDs de rol. Las claves no distinguen mayúsculas.
+func WithMentions(users, teams map[string]string) ServiceOption {
+	return func(s *webhookService) {
+		s.discordUsers = lowerKeys(users)
+		s.discordRoles = lowerKeys(teams)
+	}
+}
+
+// mentionList acumula las menciones de un payload sin repetir IDs.
+type mentionList struct {
+	users []string
+	roles []string
+}
+
+// mentionUser añade al usuario de Discord mapeado a login. Retorna false si no hay mapeo.
+func (s *webhookService) mentionUser(m *mentionList, login string) bool {
+	id, ok := s.discordUsers[strings.ToLower(login)]
+	if ok && !slices.Contains(m.users, id) && len(m.users) < maxAllowedMentions {
+		m.users = append(m.users, id)
+	}
+	return ok
+}
+
+// mentionTeam añade el rol mapeado al equipo, buscando primero "owner/slug" y luego "slug".
+func (s *webhookService) mentionTeam(m *mentionList, owner, slug string) bool {
+	id, ok := s.discordRoles[strings.ToLower(owner+"/"+slug)]
+	if !ok {
+		id, ok = s.discordRoles[strings.ToLower(slug)]
+	}
+	if ok && !slices.Contains(m.roles, id) && len(m.roles) < maxAllowedMentions {
+		m.roles = append(m.roles, id)
+	}
+	return ok
+}
+
+func (m *mentionList) empty() bool {
+	return len(m.users) == 0 && len(m.roles) == 0
+}
+
+// String retorna las menciones en formato Discord (ej: "<@123> <@&456>").
+func (m *mentionList) String() string {
+	parts := make([]string, 0, len(m.users)+len(m.roles))
+	for _, id := range m.users {
+		parts = append(parts, "<@"+id+">")
+	}
+	for _, id := range m.roles {
+		parts = append(parts, "<@&"+id+">")
+	}
+	return strings.Join(parts, " ")
+}
+
+// apply antepone las menciones al contenido del payload (con prefix) y las autoriza explícitamente.
+func (m *mentionList) apply(payload *DiscordPayload, prefix string) {
+	if m.empty() {
+		return
+	}
+	payload.Content = strings.TrimSpace(m.String() + " " + prefix)
+	payload.AllowedMentions = &AllowedMentions{Parse: []string{}, Users: m.users, Roles: m.roles}
+}
+
+// restrictMentions garantiza que un payload sin menciones explícitas no notifique a nadie
+// (ej: un "@everyone" que venga en el título de un PR).
+func restrictMentions(payload *DiscordPayload) {
+	if payload.AllowedMentions == nil {
+		payload.AllowedMentions = &AllowedMentions{Parse: []string{}}
+	}
+}
+
+// repoOwner retorna el dueño de un repositorio "owner/repo".
+func repoOwner(fullName string) string {
+	owner, _, _ := strings.Cut(fullName, "/")
+	return owner
+}
+
+func lowerKeys(values map[string]string) map[string]string {
+	lowered := make(map[string]string, len(values))
+	for key, value := range values {
+		lowered[strings.ToLower(key)] = value
+	}
+	return lowered
+}
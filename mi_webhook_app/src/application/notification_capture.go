@@ -0,0 +1,73 @@
+// File: src/application/notification_capture.go
+package application
+
+import (
+	"context"
+	"sync"
+)
+
+// notificationCaptureKey es la clave de contexto de la captura de la entrega en curso.
+type notificationCaptureKey struct{}
+
+// NotificationCapture acumula las decisiones de enrutamiento y los envíos hechos
+// mientras se procesa una entrega. Alimenta el historial, la vista previa y la simulación.
+type NotificationCapture struct {
+	mu      sync.Mutex
+	route   string
+	handled bool
+	records []NotificationRecord
+}
+
+// WithNotificationCapture adjunta una captura vacía al contexto. Si el contexto ya
+// tiene una, Process la reutiliza en lugar de crear otra.
+func WithNotificationCapture(ctx context.Context) (context.Context, *NotificationCapture) {
+	capture := &NotificationCapture{}
+	return context.WithValue(ctx, notificationCaptureKey{}, capture), capture
+}
+
+// captureFrom retorna la captura del contexto, creando una si no existe.
+func captureFrom(ctx context.Context) (context.Context, *NotificationCapture) {
+	if capture, ok := ctx.Value(notificationCaptureKey{}).(*NotificationCapture); ok {
+		return ctx, capture
+	}
+	return WithNotificationCapture(ctx)
+}
+
+// Route retorna el manejador elegido (ej: "pull_request/opened") y si hubo uno.
+func (c *NotificationCapture) Route() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.route, c.handled
+}
+
+// Records retorna una copia de los envíos capturados.
+func (c *NotificationCapture) Records() []NotificationRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]NotificationRecord{}, c.records...)
+}
+
+func (c *NotificationCapture) setRoute(route string, handled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.route = route
+	c.handled = handled
+}
+
+func (c *NotificationCapture) add(record NotificationRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = append(c.records, record)
+}
+
+// recordNotification anota un envío en la captura de la entrega, si existe.
+func recordNotification(ctx context.Context, record NotificationRecord, err error) {
+	capture, ok := ctx.Value(notificationCaptureKey{}).(*NotificationCapture)
+	if !ok {
+		return
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	capture.add(record)
+}
@@ -1,17 +1,184 @@
 // File: src/application/ports.go
 package application
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
 // NotificationService define el puerto para enviar notificaciones.
 // La capa de aplicación depende de esta interfaz, no de una implementación concreta.
+// El contexto viene de la petición (o del worker) y limita la duración del envío.
+// Retorna el mensaje publicado (su ID y el canal o hilo donde quedó) cuando el destino lo informa.
 type NotificationService interface {
-	SendNotification(channelType string, payload DiscordPayload) error
+	SendNotification(ctx context.Context, channelType string, payload DiscordPayload) (*DiscordMessage, error)
+	// EditNotification reemplaza el contenido de un mensaje publicado antes por el mismo webhook.
+	// payload.ThreadID indica el hilo donde vive el mensaje, si corresponde.
+	EditNotification(ctx context.Context, channelType, messageID string, payload DiscordPayload) (*DiscordMessage, error)
 }
 
 // WebhookProcessor define el puerto para la lógica central de la aplicación (casos de uso).
 // Adaptadores controladores (como handlers HTTP) llamarán métodos en esta interfaz.
+// El procesador despacha cada evento al manejador registrado para su par (evento, acción).
 type WebhookProcessor interface {
-	ProcessPullRequestEvent(payload []byte) error
-	ProcessWorkflowRunEvent(payload []byte) error
+	Process(ctx context.Context, event Event) error
+}
+
+// DeliveryStore define el puerto para el historial persistente de entregas recibidas.
+type DeliveryStore interface {
+	SaveDelivery(ctx context.Context, record DeliveryRecord) error
+	// GetDelivery retorna ErrDeliveryNotFound si no existe una entrega con ese ID.
+	GetDelivery(ctx context.Context, id string) (*DeliveryRecord, error)
+	// ListDeliveries retorna las entregas más recientes primero, aplicando filtros y paginación.
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]DeliveryRecord, error)
+	// PruneDeliveries borra las entregas recibidas antes de before y retorna cuántas borró.
+	PruneDeliveries(ctx context.Context, before time.Time) (int, error)
+}
+
+// ThreadStore define el puerto que recuerda en qué hilo de Discord se publica cada pull request.
+type ThreadStore interface {
+	// GetThread retorna el ID del hilo para key y si existe.
+	GetThread(ctx context.Context, key string) (string, bool, error)
+	SaveThread(ctx context.Context, key, threadID string) error
+}
+
+// StatusMessage es un mensaje publicado que se edita a medida que cambia el estado de algo
+// (ej: una ejecución de workflow que pasa de en curso a completada).
+type StatusMessage struct {
+	Destination string `json:"destination"`
+	MessageID   string `json:"message_id"`
+	ThreadID    string `json:"thread_id,omitempty"` // Hilo donde se publicó, si se publicó en uno
+}
+
+// StatusMessageStore define el puerto que recuerda los mensajes de estado editables.
+type StatusMessageStore interface {
+	// GetStatusMessage retorna el mensaje guardado para key y si existe.
+	GetStatusMessage(ctx context.Context, key string) (StatusMessage, bool, error)
+	SaveStatusMessage(ctx context.Context, key string, message StatusMessage) error
+	DeleteStatusMessage(ctx context.Context, key string) error
+}
+
+// ChangedFilesSource define el puerto que lista los archivos modificados por un pull request.
+type ChangedFilesSource interface {
+	ChangedFiles(ctx context.Context, repo string, number int) ([]string, error)
+}
+
+// CodeOwnersSource define el puerto que obtiene el CODEOWNERS de un repositorio.
+// Retorna nil (sin error) si el repositorio no tiene uno.
+type CodeOwnersSource interface {
+	CodeOwners(ctx context.Context, repo string) (*domain.CodeOwners, error)
+}
+
+// GitHubClient define el puerto de consultas a la API REST de GitHub con las que se
+// enriquecen las notificaciones. Un fallo nunca impide notificar: se usa el embed sin enriquecer.
+type GitHubClient interface {
+	PullRequestStats(ctx context.Context, repo string, number int) (*PullRequestStats, error)
+	// WorkflowRunJobs retorna los jobs del último intento de una ejecución.
+	WorkflowRunJobs(ctx context.Context, repo string, runID int64) ([]WorkflowJob, error)
+	// CheckRuns retorna los check runs de un commit (SHA, rama o tag).
+	CheckRuns(ctx context.Context, repo, ref string) ([]CheckRun, error)
+	// JobLogs retorna el log en texto plano de un job (o su final, si es muy largo).
+	JobLogs(ctx context.Context, repo string, jobID int64) ([]byte, error)
+}
+
+// InstallationStore define el puerto que recuerda las instalaciones de la GitHub App
+// y qué repositorios cubre cada una.
+type InstallationStore interface {
+	SaveInstallation(ctx context.Context, installation Installation) error
+	DeleteInstallation(ctx context.Context, id int64) error
+	// GetInstallation retorna la instalación id y si existe.
+	GetInstallation(ctx context.Context, id int64) (*Installation, bool, error)
+	// FindInstallation retorna la instalación activa que cubre el repositorio "owner/repo".
+	FindInstallation(ctx context.Context, repo string) (*Installation, bool, error)
+}
+
+// RunHistoryStore define el puerto del historial de resultados de ejecuciones de workflows
+// con el que se detectan los workflows inestables (flaky).
+type RunHistoryStore interface {
+	SaveRunResult(ctx context.Context, result RunResult) error
+	// ListRunResults retorna los resultados que cumplen filter, del más antiguo al más reciente.
+	ListRunResults(ctx context.Context, filter RunFilter) ([]RunResult, error)
+	// PruneRunResults borra los resultados completados antes de before y retorna cuántos borró.
+	PruneRunResults(ctx context.Context, before time.Time) (int, error)
+}
+
+// BranchStateStore define el puerto que recuerda si cada workflow está pasando o fallando
+// en la rama principal de cada repositorio.
+type BranchStateStore interface {
+	// GetBranchState retorna el estado guardado para key y si existe.
+	GetBranchState(ctx context.Context, key string) (BranchState, bool, error)
+	SaveBranchState(ctx context.Context, key string, state BranchState) error
+}
+
+// PullRequestStore define el puerto que sigue los pull requests abiertos y su última
+// actividad de revisión, con el que se publican los recordatorios de revisión pendiente.
+type PullRequestStore interface {
+	// GetPullRequest retorna el pull request guardado para key y si existe.
+	GetPullRequest(ctx context.Context, key string) (TrackedPullRequest, bool, error)
+	SavePullRequest(ctx context.Context, key string, pr TrackedPullRequest) error
+	DeletePullRequest(ctx context.Context, key string) error
+	ListPullRequests(ctx context.Context) ([]TrackedPullRequest, error)
+}
+
+// HeldNotificationStore define el puerto que guarda las notificaciones retenidas durante un
+// horario de silencio hasta que se publican.
+type HeldNotificationStore interface {
+	HoldNotification(ctx context.Context, held HeldNotification) error
+	// ListHeldNotifications retorna las notificaciones retenidas en el orden en que llegaron.
+	ListHeldNotifications(ctx context.Context) ([]HeldNotification, error)
+	// UpdateHeldNotification reemplaza la notificación retenida con el mismo ID (ej: tras un intento fallido).
+	UpdateHeldNotification(ctx context.Context, held HeldNotification) error
+	DeleteHeldNotification(ctx context.Context, id uint64) error
+}
+
+// ReportPublisher define el puerto de los reportes periódicos. Un planificador los dispara por nombre.
+type ReportPublisher interface {
+	PublishReport(ctx context.Context, name string) error
+	// Reports retorna los nombres de los reportes habilitados, en orden alfabético.
+	Reports() []string
+}
+
+// LifecycleMetricsSource define el puerto de las métricas del ciclo de vida de los pull requests
+// y de los deployments (estilo DORA), calculadas con el historial de entregas.
+type LifecycleMetricsSource interface {
+	LifecycleMetrics(ctx context.Context, filter MetricsFilter) (LifecycleMetrics, error)
+}
+
+// WebhookService es el servicio de aplicación completo: procesa webhooks, publica reportes
+// y calcula métricas.
+type WebhookService interface {
+	WebhookProcessor
+	ReportPublisher
+	LifecycleMetricsSource
+}
+
+// ErrDeliveryNotFound indica que el historial no contiene la entrega solicitada.
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// ErrUnknownReport indica que no hay un reporte habilitado con el nombre solicitado.
+var ErrUnknownReport = errors.New("unknown report")
+
+// ErrEventNotHandled indica que no hay un manejador registrado para el evento recibido.
+// No es un fallo: los adaptadores lo usan para responder "recibido pero no manejado".
+var ErrEventNotHandled = errors.New("event not handled")
+
+// ErrDuplicateDelivery indica que la entrega ya se procesó (GitHub la reenvió con el mismo ID).
+// Tampoco es un fallo: la entrega no se vuelve a procesar ni a registrar.
+var ErrDuplicateDelivery = errors.New("delivery already processed")
+
+// Event es un webhook entrante tal como lo recibe el adaptador controlador.
+type Event struct {
+	Name       string // Valor del header X-GitHub-Event (ej: "pull_request")
+	Action     string // Campo "action" del payload; el servicio lo completa si viene vacío
+	DeliveryID string // Valor del header X-GitHub-Delivery
+	Payload    []byte // Cuerpo crudo del webhook
+	ReplayOf   string // ID de la entrega original si es una reproducción desde el historial
 }
 
 // --- Data Transfer Object (DTO) para Notificaciones ---
@@ -20,6 +187,36 @@ type WebhookProcessor interface {
 type DiscordPayload struct {
 	Content string         `json:"content,omitempty"`
 	Embeds  []DiscordEmbed `json:"embeds,omitempty"`
+	// ThreadName crea una publicación nueva en un canal foro con este título.
+	ThreadName string `json:"thread_name,omitempty"`
+	// ThreadID publica dentro de un hilo existente; viaja como parámetro de query, no en el cuerpo.
+	ThreadID string `json:"-"`
+	// AllowedMentions limita a quién puede notificar el mensaje. El servicio siempre lo completa.
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	// Files se adjuntan al mensaje; con archivos el notificador envía multipart/form-data.
+	Files []DiscordFile `json:"-"`
+	// Critical publica el mensaje aunque el destino esté en horario de silencio (ej: main roto).
+	Critical bool `json:"-"`
+}
+
+// DiscordFile es un archivo adjunto a un mensaje.
+type DiscordFile struct {
+	Name    string
+	Content []byte
+}
+
+// AllowedMentions es el objeto allowed_mentions de Discord. Con Parse vacío solo se notifica
+// a los usuarios y roles listados explícitamente, aunque el texto contenga otras menciones.
+type AllowedMentions struct {
+	Parse []string `json:"parse"` // Siempre se serializa: ausente significa "todo"
+	Users []string `json:"users,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// DiscordMessage es el mensaje que Discord retorna al publicar con ?wait=true.
+type DiscordMessage struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"` // En un foro o hilo, es el ID del hilo
 }
 
 type DiscordEmbed struct {
@@ -38,7 +235,252 @@ type DiscordField struct {
 	Inline bool   `json:"inline,omitempty"`
 }
 
+// maxFieldValueLength es el largo máximo que Discord acepta en el valor de un campo.
+const maxFieldValueLength = 1024
+
 type DiscordFooter struct {
 	Text    string `json:"text"`
 	IconURL string `json:"icon_url,omitempty"`
-}
\ No newline at end of file
+}
+
+// --- DTOs de la API de GitHub ---
+
+// PullRequestStats resume el tamaño de un pull request.
+type PullRequestStats struct {
+	Commits      int
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+}
+
+// WorkflowJob es un job de una ejecución de workflow.
+type WorkflowJob struct {
+	ID         int64
+	Name       string
+	Status     string
+	Conclusion string
+	HTMLURL    string
+	Steps      []WorkflowStep
+}
+
+// WorkflowStep es un paso de un job.
+type WorkflowStep struct {
+	Number     int
+	Name       string
+	Conclusion string
+}
+
+// CheckRun es un check run de un commit.
+type CheckRun struct {
+	Name       string
+	Status     string // queued, in_progress o completed
+	Conclusion string
+	HTMLURL    string
+}
+
+// Installation es una instalación de la GitHub App en una cuenta (usuario u organización).
+type Installation struct {
+	ID                  int64     `json:"id"`
+	Account             string    `json:"account"`
+	RepositorySelection string    `json:"repository_selection"`   // "all" o "selected"
+	Repositories        []string  `json:"repositories,omitempty"` // "owner/repo"; con "all" puede estar incompleta
+	Suspended           bool      `json:"suspended,omitempty"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Covers indica si la instalación da acceso al repositorio "owner/repo".
+func (i Installation) Covers(repo string) bool {
+	if i.Suspended {
+		return false
+	}
+	if i.RepositorySelection == "all" {
+		owner, _, _ := strings.Cut(repo, "/")
+		return strings.EqualFold(owner, i.Account)
+	}
+	return slices.Contains(i.Repositories, repo)
+}
+
+// --- DTOs del historial de ejecuciones ---
+
+// RunResult es el resultado de un intento de una ejecución de workflow.
+type RunResult struct {
+	Repo        string    `json:"repo"`
+	WorkflowID  int64     `json:"workflow_id"`
+	Workflow    string    `json:"workflow"`
+	HeadSHA     string    `json:"head_sha"`
+	RunID       int64     `json:"run_id"`
+	Attempt     int       `json:"attempt"`
+	Conclusion  string    `json:"conclusion"` // success o failure
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// RunFilter restringe una consulta al historial de ejecuciones. Los campos vacíos no filtran.
+type RunFilter struct {
+	Repo       string
+	WorkflowID int64 // Solo se aplica junto con Repo
+	Since      time.Time
+}
+
+// BranchState es el estado de un workflow en una rama: pasando o fallando desde Since.
+type BranchState struct {
+	Failing   bool      `json:"failing"`
+	Since     time.Time `json:"since"`
+	LastRunAt time.Time `json:"last_run_at"` // Descarta ejecuciones que llegan fuera de orden
+	// Mientras falla: el primer commit que falló, quién lo subió y cuántas ejecuciones fallaron seguidas
+	FirstSHA    string `json:"first_sha,omitempty"`
+	FirstRunURL string `json:"first_run_url,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Failures    int    `json:"failures,omitempty"`
+}
+
+// TrackedPullRequest es un pull request abierto tal como lo sigue el servicio.
+type TrackedPullRequest struct {
+	Repo      string   `json:"repo"`
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	URL       string   `json:"url"`
+	Author    string   `json:"author"`
+	Draft     bool     `json:"draft"`
+	Reviewers []string `json:"reviewers,omitempty"` // Logins con revisión pedida
+	Teams     []string `json:"teams,omitempty"`     // Slugs de los equipos con revisión pedida
+	Labels    []string `json:"labels,omitempty"`
+	// LastActivity es la última actividad de revisión (apertura, revisión pedida o enviada)
+	LastActivity time.Time `json:"last_activity"`
+	RemindedAt   time.Time `json:"reminded_at"`
+}
+
+// HeldNotification es una notificación retenida por un horario de silencio.
+type HeldNotification struct {
+	ID          uint64         `json:"id"` // Lo asigna el almacenamiento, en orden de llegada
+	Destination string         `json:"destination"`
+	Route       string         `json:"route,omitempty"`
+	Payload     DiscordPayload `json:"payload"`
+	// ThreadID, ThreadKey y Files no viajan en el JSON del payload y se guardan aparte
+	ThreadID  string        `json:"thread_id,omitempty"`
+	ThreadKey string        `json:"thread_key,omitempty"` // Hilo del pull request que se crea al publicar
+	Files     []DiscordFile `json:"files,omitempty"`
+	HeldAt    time.Time     `json:"held_at"`
+	ReleaseAt time.Time     `json:"release_at"` // Fin del horario de silencio
+	// Publicaciones fallidas: tras varias se aparta como dead letter y la tarea ya no la publica
+	Attempts   int       `json:"attempts,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	FailedAt   time.Time `json:"failed_at,omitempty"`
+	DeadLetter bool      `json:"dead_letter,omitempty"`
+}
+
+// --- DTOs de las métricas del ciclo de vida ---
+
+// MetricsFilter restringe el cálculo de las métricas. Sin Since ni Until cubre los últimos 30 días.
+type MetricsFilter struct {
+	Repo  string // "owner/repo"; vacío = todos
+	Since time.Time
+	Until time.Time
+}
+
+// LifecycleMetrics son las métricas de un período. Los tiempos van en segundos.
+type LifecycleMetrics struct {
+	Repo         string             `json:"repo,omitempty"`
+	Since        time.Time          `json:"since"`
+	Until        time.Time          `json:"until"`
+	PullRequests PullRequestMetrics `json:"pull_requests"`
+	Deployments  DeploymentMetrics  `json:"deployments"`
+}
+
+// PullRequestMetrics resume los pull requests fusionados en el período.
+type PullRequestMetrics struct {
+	Merged            int          `json:"merged"`
+	TimeToFirstReview Distribution `json:"time_to_first_review_seconds"` // Desde la creación; solo los revisados
+	TimeToMerge       Distribution `json:"time_to_merge_seconds"`        // Desde la creación
+	ReviewIterations  Distribution `json:"review_iterations"`            // Rondas de revisión: 1 + cambios pedidos; 0 sin revisión
+	Size              Distribution `json:"size_lines"`                   // Líneas añadidas + eliminadas
+}
+
+// DeploymentMetrics resume los deployments terminados en el período.
+type DeploymentMetrics struct {
+	Environments      []string     `json:"environments,omitempty"` // Entornos que cuentan; vacío = todos
+	Successful        int          `json:"successful"`
+	Failed            int          `json:"failed"` // Estado failure o error
+	PerDay            float64      `json:"per_day"`
+	LeadTime          Distribution `json:"lead_time_seconds"`   // De la creación del pull request a su primer deployment exitoso
+	ChangeFailureRate float64      `json:"change_failure_rate"` // Fallidos / terminados (0 a 1)
+}
+
+// Distribution resume una serie de muestras.
+type Distribution struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+}
+
+// --- DTOs del historial de entregas ---
+
+// Resultados del procesamiento de una entrega.
+const (
+	OutcomeProcessed = "processed" // Procesada correctamente (con o sin notificación)
+	OutcomeIgnored   = "ignored"   // No hay manejador para el evento/acción
+	OutcomeError     = "error"     // Falló el procesamiento
+)
+
+// DeliveryRecord es una entrega de webhook tal como se guarda en el historial.
+type DeliveryRecord struct {
+	ID            string               `json:"id"`
+	Event         string               `json:"event"`
+	Action        string               `json:"action,omitempty"`
+	Repo          string               `json:"repo,omitempty"`
+	Sender        string               `json:"sender,omitempty"`
+	ReceivedAt    time.Time            `json:"received_at"`
+	ReplayOf      string               `json:"replay_of,omitempty"`
+	Outcome       string               `json:"outcome"`
+	Error         string               `json:"error,omitempty"`
+	Notifications []NotificationRecord `json:"notifications,omitempty"`
+	Summary       *DeliverySummary     `json:"summary,omitempty"` // Datos del payload que usan los digests
+	Payload       json.RawMessage      `json:"payload,omitempty"` // Solo si se guardan los payloads crudos
+}
+
+// DeliverySummary guarda lo que los digests y las métricas necesitan de una entrega, aunque no se guarde el payload crudo.
+// Solo se completan los campos del tipo de evento recibido.
+type DeliverySummary struct {
+	Number     int    `json:"number,omitempty"` // Pull request (también en sus revisiones)
+	Title      string `json:"title,omitempty"`  // Título del pull request o nombre del release
+	URL        string `json:"url,omitempty"`
+	Author     string `json:"author,omitempty"`
+	Draft      bool   `json:"draft,omitempty"`
+	Merged     bool   `json:"merged,omitempty"`
+	Workflow   string `json:"workflow,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+
+	// Para las métricas del ciclo de vida de los pull requests y de los deployments
+	CreatedAt   *time.Time `json:"created_at,omitempty"` // Creación del pull request
+	MergedAt    *time.Time `json:"merged_at,omitempty"`
+	Additions   int        `json:"additions,omitempty"`
+	Deletions   int        `json:"deletions,omitempty"`
+	State       string     `json:"state,omitempty"` // Estado de la revisión o del deployment
+	Environment string     `json:"environment,omitempty"`
+}
+
+// NotificationRecord resume un envío realizado al procesar una entrega.
+// En modo dry-run no se publica nada y se guarda el payload renderizado.
+type NotificationRecord struct {
+	Destination string          `json:"destination"`
+	DryRun      bool            `json:"dry_run,omitempty"`
+	MessageID   string          `json:"message_id,omitempty"` // Mensaje editado; vacío en publicaciones nuevas
+	Payload     *DiscordPayload `json:"payload,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	QuietHours  string          `json:"quiet_hours,omitempty"` // "held" o "dropped" si cayó en horario de silencio
+}
+
+// DeliveryFilter restringe una consulta al historial. Los campos vacíos no filtran.
+type DeliveryFilter struct {
+	Repo    string
+	Event   string
+	Outcome string
+	Since   time.Time // Inclusivo
+	Until   time.Time // Exclusivo
+	Limit   int
+	Offset  int
+	// BeforeID continúa desde la última entrega de la página anterior (recibida en Until): también
+	// incluye las recibidas en Until que van antes que ella. Evita recorrer de nuevo lo ya leído.
+	BeforeID string
+}
@@ -0,0 +1,220 @@
+// File: src/application/pull_request_events.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// Las notificaciones de pull requests van al canal de desarrollo.
+const pullRequestChannel = "development"
+
+func init() {
+	registerEventModule(registerPullRequestHandlers)
+}
+
+// registerPullRequestHandlers registra los manejadores de las acciones pull_request soportadas.
+func registerPullRequestHandlers(s *webhookService) {
+	s.registry.Register("pull_request", "opened", decodeEvent(s.handlePullRequestOpened))
+	s.registry.Register("pull_request", "reopened", decodeEvent(s.handlePullRequestReopened))
+	s.registry.Register("pull_request", "ready_for_review", decodeEvent(s.handlePullRequestReadyForReview))
+	s.registry.Register("pull_request", "review_requested", decodeEvent(s.handlePullRequestReviewRequested))
+	s.registry.Register("pull_request", "closed", decodeEvent(s.handlePullRequestClosed))
+}
+
+// handlePullRequestOpened notifica un pull request nuevo.
+func (s *webhookService) handlePullRequestOpened(ctx context.Context, _ Event, event *domain.PullRequestEventPayload) error {
+	pr := event.PullRequest
+	repo := event.Repository
+
+	owners := s.resolveCodeOwners(ctx, repo.FullName, event.Number, pr.User.Login)
+	stats := s.pullRequestStatsFields(ctx, repo.FullName, pr)
+	timestamp := time.Now().Format(time.RFC3339) // Usa tiempo actual por defecto
+	if pr.CreatedAt != nil {                     // Verifica si CreatedAt está disponible
+		timestamp = pr.CreatedAt.Format(time.RFC3339)
+	}
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("🚀 New Pull Request #%d: %s", event.Number, pr.Title),
+		Description: fmt.Sprintf("A new pull request was opened in [%s](%s).", repo.FullName, repo.HTMLURL),
+		URL:         pr.HTMLURL,
+		Color:       3447003, // Azul
+		Fields:      slices.Concat(pullRequestFields(pr), stats, reviewerFields(pr), codeOwnerFields(owners)),
+		Footer:      &DiscordFooter{Text: fmt.Sprintf("Triggered by %s", event.Sender.Login)},
+		Timestamp:   timestamp,
+	}
+	payload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
+	pings := s.mentionReviewers(pr, repo)
+	s.mentionOwners(pings, owners)
+	pings.apply(&payload, "review requested")
+	renderSpan.End()
+	return s.notifyPullRequest(ctx, pullRequestChannel, repo.FullName, event.Number, pr.Title, payload)
+}
+
+// handlePullRequestReopened notifica un pull request reabierto.
+func (s *webhookService) handlePullRequestReopened(ctx context.Context, _ Event, event *domain.PullRequestEventPayload) error {
+	pr := event.PullRequest
+	repo := event.Repository
+
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("🔄 Pull Request Reopened #%d: %s", event.Number, pr.Title),
+		Description: fmt.Sprintf("Pull request reopened in [%s](%s).", repo.FullName, repo.HTMLURL),
+		URL:         pr.HTMLURL,
+		Color:       16776960, // Amarillo
+		Fields:      pullRequestFields(pr),
+		Footer:      &DiscordFooter{Text: fmt.Sprintf("Reopened by %s", event.Sender.Login)},
+		Timestamp:   updatedTimestamp(pr),
+	}
+	renderSpan.End()
+	return s.notifyPullRequest(ctx, pullRequestChannel, repo.FullName, event.Number, pr.Title, DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}})
+}
+
+// handlePullRequestReadyForReview notifica un pull request marcado como listo para revisión.
+func (s *webhookService) handlePullRequestReadyForReview(ctx context.Context, _ Event, event *domain.PullRequestEventPayload) error {
+	pr := event.PullRequest
+	repo := event.Repository
+	owners := s.resolveCodeOwners(ctx, repo.FullName, event.Number, pr.User.Login)
+	stats := s.pullRequestStatsFields(ctx, repo.FullName, pr)
+	checks := s.checkRunsFields(ctx, repo.FullName, pr.Head.Sha)
+
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("👀 PR Ready for Review #%d: %s", event.Number, pr.Title),
+		Description: fmt.Sprintf("Pull request marked as ready for review in [%s](%s).", repo.FullName, repo.HTMLURL),
+		URL:         pr.HTMLURL,
+		Color:       3066993, // Verde
+		Fields:      slices.Concat(pullRequestFields(pr), stats, checks, reviewerFields(pr), codeOwnerFields(owners)),
+		Footer:      &DiscordFooter{Text: fmt.Sprintf("Marked ready by %s", event.Sender.Login)},
+		Timestamp:   updatedTimestamp(pr),
+	}
+	payload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
+	pings := s.mentionReviewers(pr, repo)
+	s.mentionOwners(pings, owners)
+	pings.apply(&payload, "review requested")
+	renderSpan.End()
+	return s.notifyPullRequest(ctx, pullRequestChannel, repo.FullName, event.Number, pr.Title, payload)
+}
+
+// handlePullRequestReviewRequested notifica una revisión pedida a un usuario o equipo.
+// GitHub envía un evento por cada revisor; en borradores no se notifica (aún no está listo).
+func (s *webhookService) handlePullRequestReviewRequested(ctx context.Context, _ Event, event *domain.PullRequestEventPayload) error {
+	pr := event.PullRequest
+	repo := event.Repository
+
+	if pr.Draft {
+		Logger(ctx).Info("Review requested on a draft pull request, no notification sent", "pr", event.Number)
+		return nil
+	}
+
+	var reviewer string
+	var pings mentionList
+	switch {
+	case event.RequestedReviewer != nil:
+		reviewer = fmt.Sprintf("[%s](%s)", event.RequestedReviewer.Login, event.RequestedReviewer.HTMLURL)
+		s.mentionUser(&pings, event.RequestedReviewer.Login)
+	case event.RequestedTeam != nil:
+		reviewer = fmt.Sprintf("[%s](%s) (team)", event.RequestedTeam.Name, event.RequestedTeam.HTMLURL)
+		s.mentionTeam(&pings, repoOwner(repo.FullName), event.RequestedTeam.Slug)
+	default:
+		Logger(ctx).Info("Review requested without reviewer, no notification sent", "pr", event.Number)
+		return nil
+	}
+
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("🔍 Review Requested #%d: %s", event.Number, pr.Title),
+		Description: fmt.Sprintf("Review requested in [%s](%s).", repo.FullName, repo.HTMLURL),
+		URL:         pr.HTMLURL,
+		Color:       3447003, // Azul
+		Fields: append(pullRequestFields(pr),
+			DiscordField{Name: "Reviewer", Value: reviewer, Inline: true}),
+		Footer:    &DiscordFooter{Text: fmt.Sprintf("Requested by %s", event.Sender.Login)},
+		Timestamp: updatedTimestamp(pr),
+	}
+	payload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
+	pings.apply(&payload, "your review was requested")
+	renderSpan.End()
+	return s.notifyPullRequest(ctx, pullRequestChannel, repo.FullName, event.Number, pr.Title, payload)
+}
+
+// handlePullRequestClosed notifica un pull request fusionado; los cerrados sin merge se ignoran.
+func (s *webhookService) handlePullRequestClosed(ctx context.Context, _ Event, event *domain.PullRequestEventPayload) error {
+	pr := event.PullRequest
+	repo := event.Repository
+	sender := event.Sender
+
+	if !pr.Merged {
+		Logger(ctx).Info("Pull request closed without merging, no notification sent", "pr", event.Number)
+		return nil // No es un error, solo ignorado
+	}
+
+	stats := s.pullRequestStatsFields(ctx, repo.FullName, pr)
+	timestamp := time.Now().Format(time.RFC3339)
+	if pr.MergedAt != nil { // Verifica si MergedAt está disponible
+		timestamp = pr.MergedAt.Format(time.RFC3339)
+	}
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("✅ Pull Request Merged #%d: %s", event.Number, pr.Title),
+		Description: fmt.Sprintf("Pull request successfully merged into `%s` in [%s](%s).", pr.Base.Ref, repo.FullName, repo.HTMLURL),
+		URL:         pr.HTMLURL,
+		Color:       8359053, // Púrpura
+		Fields: append([]DiscordField{
+			{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
+			{Name: "Merged By", Value: fmt.Sprintf("[%s](%s)", sender.Login, sender.HTMLURL), Inline: true}, // Asume que sender es quien hizo merge
+		}, stats...),
+		Footer:    &DiscordFooter{Text: "Merged"},
+		Timestamp: timestamp,
+	}
+	renderSpan.End()
+	return s.notifyPullRequest(ctx, pullRequestChannel, repo.FullName, event.Number, pr.Title, DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}})
+}
+
+// pullRequestFields construye los campos comunes de autor y rama.
+func pullRequestFields(pr domain.PullRequest) []DiscordField {
+	return []DiscordField{
+		{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
+		{Name: "Branch", Value: fmt.Sprintf("`%s` → `%s`", pr.Head.Ref, pr.Base.Ref), Inline: true},
+	}
+}
+
+// reviewerFields lista los revisores (usuarios y equipos) pedidos, si hay alguno.
+func reviewerFields(pr domain.PullRequest) []DiscordField {
+	var reviewers []string
+	for _, user := range pr.RequestedReviewers {
+		reviewers = append(reviewers, fmt.Sprintf("[%s](%s)", user.Login, user.HTMLURL))
+	}
+	for _, team := range pr.RequestedTeams {
+		reviewers = append(reviewers, fmt.Sprintf("[%s](%s) (team)", team.Name, team.HTMLURL))
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+	return []DiscordField{{Name: "Reviewers", Value: truncate(strings.Join(reviewers, ", "), maxFieldValueLength)}}
+}
+
+// mentionReviewers menciona a los revisores pedidos que tienen usuario o rol de Discord.
+func (s *webhookService) mentionReviewers(pr domain.PullRequest, repo domain.Repository) *mentionList {
+	pings := &mentionList{}
+	for _, user := range pr.RequestedReviewers {
+		s.mentionUser(pings, user.Login)
+	}
+	for _, team := range pr.RequestedTeams {
+		s.mentionTeam(pings, repoOwner(repo.FullName), team.Slug)
+	}
+	return pings
+}
+
+// updatedTimestamp usa UpdatedAt si está disponible o el tiempo actual en su defecto.
+func updatedTimestamp(pr domain.PullRequest) string {
+	if pr.UpdatedAt != nil {
+		return pr.UpdatedAt.Format(time.RFC3339)
+	}
+	return time.Now().Format(time.RFC3339)
+}
@@ -0,0 +1,87 @@
+// File: src/application/pull_request_review_events.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// maxReviewBodyLength recorta el comentario de la revisión en la descripción del embed.
+const maxReviewBodyLength = 500
+
+func init() {
+	registerEventModule(registerPullRequestReviewHandlers)
+}
+
+// registerPullRequestReviewHandlers registra los manejadores de pull_request_review.
+// Las revisiones van al mismo canal (y al mismo hilo) que su pull request.
+func registerPullRequestReviewHandlers(s *webhookService) {
+	s.registry.Register("pull_request_review", "submitted", decodeEvent(s.handlePullRequestReviewSubmitted))
+}
+
+// handlePullRequestReviewSubmitted notifica una revisión enviada.
+func (s *webhookService) handlePullRequestReviewSubmitted(ctx context.Context, _ Event, event *domain.PullRequestReviewEventPayload) error {
+	review := event.Review
+	pr := event.PullRequest
+	repo := event.Repository
+
+	var color int
+	var title, ping string
+	switch review.State {
+	case "approved":
+		color = 3066993 // Verde
+		title = fmt.Sprintf("✅ PR Approved #%d: %s", pr.Number, pr.Title)
+		ping = "your pull request was approved"
+	case "changes_requested":
+		color = 15158332 // Rojo
+		title = fmt.Sprintf("📝 Changes Requested #%d: %s", pr.Number, pr.Title)
+		ping = "changes were requested on your pull request"
+	case "commented":
+		color = 9807270 // Gris
+		title = fmt.Sprintf("💬 PR Reviewed #%d: %s", pr.Number, pr.Title)
+		ping = "your pull request has a new review"
+	default:
+		Logger(ctx).Info("Unhandled review state, no notification sent", "state", review.State, "pr", pr.Number)
+		return nil
+	}
+
+	stats := s.pullRequestStatsFields(ctx, repo.FullName, pr)
+	timestamp := time.Now().Format(time.RFC3339)
+	if review.SubmittedAt != nil {
+		timestamp = review.SubmittedAt.Format(time.RFC3339)
+	}
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       title,
+		Description: truncate(review.Body, maxReviewBodyLength),
+		URL:         review.HTMLURL,
+		Color:       color,
+		Fields: append([]DiscordField{
+			{Name: "Reviewer", Value: fmt.Sprintf("[%s](%s)", review.User.Login, review.User.HTMLURL), Inline: true},
+			{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
+			{Name: "Repository", Value: fmt.Sprintf("[%s](%s)", repo.FullName, repo.HTMLURL), Inline: true},
+		}, stats...),
+		Footer:    &DiscordFooter{Text: fmt.Sprintf("Reviewed by %s", review.User.Login)},
+		Timestamp: timestamp,
+	}
+	payload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
+	// Avisa al autor, salvo que sea él mismo quien comenta su PR
+	var pings mentionList
+	if review.User.Login != pr.User.Login {
+		s.mentionUser(&pings, pr.User.Login)
+	}
+	pings.apply(&payload, ping)
+	renderSpan.End()
+	return s.notifyPullRequest(ctx, pullRequestChannel, repo.FullName, pr.Number, pr.Title, payload)
+}
+
+// truncate recorta text a max runas, marcando el corte con "…".
+func truncate(text string, max int) string {
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max-1]) + "…"
+	}
+	return text
+}
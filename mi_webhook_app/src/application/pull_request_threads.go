@@ -0,0 +1,87 @@
+// File: src/application/pull_request_threads.go
+package application
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxThreadNameLength es el largo máximo que Discord acepta para el nombre de un hilo.
+const maxThreadNameLength = 100
+
+// WithPullRequestThreads agrupa las notificaciones de cada pull request en un hilo propio
+// en los destinos indicados (canales foro o de texto con hilos en Discord).
+func WithPullRequestThreads(store ThreadStore, destinations ...string) ServiceOption {
+	return func(s *webhookService) {
+		s.threads = store
+		s.threadDestinations = make(map[string]bool, len(destinations))
+		for _, destination := range destinations {
+			s.threadDestinations[destination] = true
+		}
+	}
+}
+
+// notifyPullRequest envía una notificación de un pull request a su hilo. El primer envío crea el
+// hilo (thread_name) y guarda su ID; los siguientes publican dentro de él (thread_id).
+// Si el destino no usa hilos se comporta igual que notify.
+func (s *webhookService) notifyPullRequest(ctx context.Context, channelType, repo string, number int, title string, payload DiscordPayload) error {
+	_, _, err := s.sendPullRequest(ctx, channelType, repo, number, title, payload)
+	return err
+}
+
+// sendPullRequest es notifyPullRequest retornando el mensaje publicado y el hilo donde quedó
+// ("" si el destino no usa hilos).
+func (s *webhookService) sendPullRequest(ctx context.Context, channelType, repo string, number int, title string, payload DiscordPayload) (*DiscordMessage, string, error) {
+	destination := resolveDestination(ctx, channelType)
+	if s.threads == nil || !s.threadDestinations[destination] || number == 0 {
+		message, err := s.send(ctx, channelType, payload)
+		return message, "", err
+	}
+
+	key := threadKey(destination, repo, number)
+	threadID, found, err := s.threads.GetThread(ctx, key)
+	if err != nil {
+		// Sin el mapeo se abre un hilo nuevo: es mejor duplicar el hilo que perder la notificación
+		Logger(ctx).Error("Reading pull request thread", "thread_key", key, "error", err)
+	}
+	if found {
+		payload.ThreadID = threadID
+	} else {
+		payload.ThreadName = threadName(repo, number, title)
+		// Si el envío se retiene, el hilo se guarda al publicarlo; no se agrupa porque se necesita su ID
+		ctx = withoutCoalescing(withThreadKey(ctx, key))
+	}
+
+	message, err := s.send(ctx, channelType, payload)
+	if err != nil {
+		return nil, "", err
+	}
+	// En dry-run o en horario de silencio no hay mensaje: el hilo no existe y no se guarda nada
+	if message == nil {
+		return nil, "", nil
+	}
+	if found || message.ChannelID == "" {
+		return message, threadID, nil
+	}
+	if err := s.threads.SaveThread(ctx, key, message.ChannelID); err != nil {
+		Logger(ctx).Error("Saving pull request thread", "thread_key", key, "error", err)
+		return message, message.ChannelID, nil // La notificación ya se publicó
+	}
+	Logger(ctx).Info("Created pull request thread", "thread_key", key, "thread_id", message.ChannelID)
+	return message, message.ChannelID, nil
+}
+
+// threadKey identifica el hilo de un pull request dentro de un destino.
+func threadKey(destination, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", destination, repo, number)
+}
+
+// threadName arma el título del hilo (ej: "#42 Add retries"), recortado al límite de Discord.
+// Sin título (ej: eventos de CI) usa "repo#42".
+func threadName(repo string, number int, title string) string {
+	name := fmt.Sprintf("%s#%d", repo, number)
+	if title != "" {
+		name = fmt.Sprintf("#%d %s", number, title)
+	}
+	return truncate(name, maxThreadNameLength)
+}
@@ -0,0 +1,248 @@
+// File: src/application/quiet_hours.go
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ReleaseHeldReport es la tarea periódica que publica las notificaciones retenidas cuyo
+// horario de silencio terminó.
+const ReleaseHeldReport = "quiet_hours"
+
+// maxReleaseAttempts es cuántas veces se intenta publicar una notificación retenida antes de
+// apartarla como dead letter (con la tarea cada 5 minutos, cerca de una hora).
+const maxReleaseAttempts = 12
+
+// deadLetterRetention es cuánto se conservan las notificaciones apartadas antes de borrarlas.
+const deadLetterRetention = 7 * 24 * time.Hour
+
+// QuietHours es un horario de silencio diario de Start a End (desde la medianoche) en Location.
+// Si End es anterior a Start el horario cruza la medianoche (ej: 22:00-07:00).
+type QuietHours struct {
+	Start, End time.Duration
+	Location   *time.Location // nil = UTC
+	Drop       bool           // Descarta las notificaciones en lugar de retenerlas
+}
+
+// window retorna si t cae en el horario de silencio y, en ese caso, cuándo termina.
+func (q QuietHours) window(t time.Time) (bool, time.Time) {
+	location := q.Location
+	if location == nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	clock := clockTime(local)
+	switch {
+	case q.Start < q.End && clock >= q.Start && clock < q.End:
+		return true, atClock(local, q.End)
+	case q.Start > q.End && clock >= q.Start:
+		return true, atClock(local.AddDate(0, 0, 1), q.End)
+	case q.Start > q.End && clock < q.End:
+		return true, atClock(local, q.End)
+	default:
+		return false, time.Time{}
+	}
+}
+
+// clockTime retorna la hora que marca el reloj en t, como tiempo desde la medianoche.
+// Los días de cambio de horario difiere del tiempo transcurrido desde la medianoche.
+func clockTime(t time.Time) time.Duration {
+	hour, minute, second := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(t.Nanosecond())
+}
+
+// atClock retorna el instante del día de day (en su zona) en que el reloj marca offset. Se arma
+// con time.Date: sumar offset a la medianoche se corre una hora los días de cambio de horario.
+func atClock(day time.Time, offset time.Duration) time.Time {
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	second, nanos := int(offset%time.Minute/time.Second), int(offset%time.Second)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, nanos, day.Location())
+}
+
+// WithQuietHours silencia las notificaciones no críticas durante los horarios indicados por
+// destino (ej: "testing") o por ruta (ej: "workflow_run/completed"); la ruta tiene prioridad.
+// Las retenidas se guardan en store y las publica la tarea ReleaseHeldReport al terminar el horario,
+// agrupadas en mensajes de hasta 10 embeds. Las ediciones de mensajes ya publicados no se silencian.
+func WithQuietHours(store HeldNotificationStore, windows map[string]QuietHours) ServiceOption {
+	return func(s *webhookService) {
+		s.heldNotifications = store
+		s.quietHours = windows
+		s.registerReport(ReleaseHeldReport, s.releaseHeldNotifications)
+	}
+}
+
+// holdKey es la clave de contexto de los envíos que no pasan por el horario de silencio.
+type holdKey struct{}
+
+// threadKeyContextKey lleva el hilo del pull request que se crearía con el envío en curso.
+type threadKeyContextKey struct{}
+
+func withThreadKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, threadKeyContextKey{}, key)
+}
+
+// quietWindow busca el horario de silencio activo para el envío: primero el de la ruta de la
+// entrega y luego el del destino.
+func (s *webhookService) quietWindow(ctx context.Context, destination string, now time.Time) (QuietHours, time.Time, bool) {
+	if len(s.quietHours) == 0 || ctx.Value(holdKey{}) != nil {
+		return QuietHours{}, time.Time{}, false
+	}
+	_, capture := captureFrom(ctx)
+	route, _ := capture.Route()
+	for _, key := range []string{route, destination} {
+		window, ok := s.quietHours[key]
+		if !ok || key == "" {
+			continue
+		}
+		active, end := window.window(now)
+		return window, end, active
+	}
+	return QuietHours{}, time.Time{}, false
+}
+
+// holdNotification retiene o descarta un envío que cayó en horario de silencio.
+func (s *webhookService) holdNotification(ctx context.Context, destination string, payload DiscordPayload, window QuietHours, releaseAt time.Time) error {
+	if window.Drop {
+		Logger(ctx).Info("Quiet hours: notification dropped")
+		recordNotification(ctx, NotificationRecord{Destination: destination, QuietHours: "dropped"}, nil)
+		return nil
+	}
+	_, capture := captureFrom(ctx)
+	route, _ := capture.Route()
+	threadKey, _ := ctx.Value(threadKeyContextKey{}).(string)
+	held := HeldNotification{
+		Destination: destination,
+		Route:       route,
+		Payload:     payload,
+		ThreadID:    payload.ThreadID,
+		ThreadKey:   threadKey,
+		Files:       payload.Files,
+		HeldAt:      time.Now().UTC(),
+		ReleaseAt:   releaseAt.UTC(),
+	}
+	err := s.heldNotifications.HoldNotification(ctx, held)
+	recordNotification(ctx, NotificationRecord{Destination: destination, QuietHours: "held"}, err)
+	if err != nil {
+		Logger(ctx).Error("Holding notification failed", "error", err)
+		return fmt.Errorf("failed to hold %s notification: %w", destination, err)
+	}
+	Logger(ctx).Info("Quiet hours: notification held", "release_at", held.ReleaseAt.Format(time.RFC3339))
+	return nil
+}
+
+// releaseHeldNotifications publica las notificaciones retenidas cuyo horario terminó. Las de un
+// mismo destino e hilo se agrupan; si un envío falla, las siguientes quedan para la próxima vez.
+// Las apartadas como dead letter no se publican y se borran pasada deadLetterRetention.
+func (s *webhookService) releaseHeldNotifications(ctx context.Context) error {
+	held, err := s.heldNotifications.ListHeldNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list held notifications: %w", err)
+	}
+	now := time.Now()
+	for _, n := range held {
+		if n.DeadLetter && now.Sub(n.FailedAt) > deadLetterRetention {
+			if err := s.heldNotifications.DeleteHeldNotification(ctx, n.ID); err != nil {
+				Logger(ctx).Error("Deleting dead-lettered notification", "held_id", n.ID, "error", err)
+			}
+		}
+	}
+	held = slices.DeleteFunc(held, func(n HeldNotification) bool { return n.DeadLetter || now.Before(n.ReleaseAt) })
+	if len(held) == 0 {
+		return nil
+	}
+	Logger(ctx).Info("Releasing held notifications", "count", len(held))
+
+	// Agrupa por destino e hilo conservando el orden de llegada
+	type group struct {
+		destination, threadID, threadKey, threadName string
+		notifications                                []HeldNotification
+	}
+	var groups []*group
+	for _, n := range held {
+		threadName := n.Payload.ThreadName
+		i := slices.IndexFunc(groups, func(g *group) bool {
+			return g.destination == n.Destination && g.threadID == n.ThreadID && g.threadKey == n.ThreadKey && g.threadName == threadName
+		})
+		if i < 0 {
+			groups = append(groups, &group{destination: n.Destination, threadID: n.ThreadID, threadKey: n.ThreadKey, threadName: threadName})
+			i = len(groups) - 1
+		}
+		groups[i].notifications = append(groups[i].notifications, n)
+	}
+
+	ctx = context.WithValue(ctx, holdKey{}, true)
+	var errs []error
+	for _, g := range groups {
+		if err := s.releaseGroup(ctx, g.destination, g.threadKey, g.notifications); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// releaseGroup publica las notificaciones retenidas de un destino e hilo en mensajes agrupados.
+func (s *webhookService) releaseGroup(ctx context.Context, destination, threadKey string, held []HeldNotification) error {
+	payloads := make([]DiscordPayload, len(held))
+	for i, n := range held {
+		payloads[i] = n.Payload
+		payloads[i].ThreadID, payloads[i].Files = n.ThreadID, n.Files
+	}
+	// El hilo del pull request pudo crearse durante el silencio (ej: con un aviso crítico)
+	threadID := payloads[0].ThreadID
+	if threadKey != "" && s.threads != nil {
+		if id, found, err := s.threads.GetThread(ctx, threadKey); err == nil && found {
+			threadID = id
+		}
+	}
+
+	sent := 0
+	for _, batch := range CoalescePayloads(payloads) {
+		if threadID != "" {
+			batch.Payload.ThreadID, batch.Payload.ThreadName = threadID, ""
+		}
+		message, err := s.send(ctx, destination, batch.Payload)
+		if err != nil {
+			s.recordReleaseFailure(ctx, held[sent:sent+batch.Count], err)
+			return err
+		}
+		if threadID == "" && batch.Payload.ThreadName != "" && message != nil && message.ChannelID != "" {
+			threadID = message.ChannelID
+			if threadKey != "" && s.threads != nil {
+				if err := s.threads.SaveThread(ctx, threadKey, threadID); err != nil {
+					Logger(ctx).Error("Saving pull request thread", "thread_key", threadKey, "error", err)
+				}
+			}
+		}
+		for _, n := range held[sent : sent+batch.Count] {
+			if err := s.heldNotifications.DeleteHeldNotification(ctx, n.ID); err != nil {
+				Logger(ctx).Error("Deleting released notification", "held_id", n.ID, "error", err)
+			}
+		}
+		sent += batch.Count
+	}
+	return nil
+}
+
+// recordReleaseFailure anota el intento fallido en las notificaciones del mensaje rechazado. Tras
+// maxReleaseAttempts se apartan como dead letter: un mensaje que Discord rechaza siempre no debe
+// frenar al resto de su grupo en cada ejecución de la tarea.
+func (s *webhookService) recordReleaseFailure(ctx context.Context, held []HeldNotification, releaseErr error) {
+	now := time.Now().UTC()
+	for _, n := range held {
+		n.Attempts++
+		n.LastError, n.FailedAt = releaseErr.Error(), now
+		if n.Attempts >= maxReleaseAttempts {
+			n.DeadLetter = true
+			Logger(ctx).Error("Held notification dead-lettered after repeated release failures",
+				"held_id", n.ID, "attempts", n.Attempts, "error", releaseErr)
+		}
+		if err := s.heldNotifications.UpdateHeldNotification(ctx, n); err != nil {
+			Logger(ctx).Error("Updating held notification", "held_id", n.ID, "error", err)
+		}
+	}
+}
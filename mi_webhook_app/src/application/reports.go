@@ -0,0 +1,41 @@
+// File: src/application/reports.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// registerReport habilita un reporte periódico. Se llama desde las ServiceOption que lo activan.
+func (s *webhookService) registerReport(name string, report func(ctx context.Context) error) {
+	s.reports[name] = report
+}
+
+// PublishReport implementa ReportPublisher. Retorna ErrUnknownReport si el reporte no está habilitado.
+func (s *webhookService) PublishReport(ctx context.Context, name string) error {
+	report, ok := s.reports[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownReport, name)
+	}
+	ctx = WithLogAttrs(ctx, "report", name)
+	ctx, span := startSpan(ctx, "report."+name)
+	Logger(ctx).Info("Publishing report")
+	err := report(ctx)
+	endSpan(span, err)
+	if err != nil {
+		Logger(ctx).Error("Publishing report failed", "error", err)
+		return fmt.Errorf("failed to publish %s report: %w", name, err)
+	}
+	return nil
+}
+
+// Reports implementa ReportPublisher.
+func (s *webhookService) Reports() []string {
+	names := make([]string, 0, len(s.reports))
+	for name := range s.reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
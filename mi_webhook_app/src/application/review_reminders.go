@@ -0,0 +1,240 @@
+// File: src/application/review_reminders.go
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// ReviewReminderReport es el reporte que recuerda los pull requests que esperan revisión.
+const ReviewReminderReport = "stale_prs"
+
+// WorkingHours es un calendario laboral: los días marcados en Days, de Start a End
+// (desde la medianoche) en Location. Sin días marcados cuenta todo el tiempo transcurrido.
+type WorkingHours struct {
+	Days       [7]bool // Indexado por time.Weekday
+	Start, End time.Duration
+	Location   *time.Location // nil = UTC
+}
+
+// elapsed retorna el tiempo laboral entre from y to.
+func (w WorkingHours) elapsed(from, to time.Time) time.Duration {
+	if !slices.Contains(w.Days[:], true) {
+		return max(to.Sub(from), 0)
+	}
+	location := w.Location
+	if location == nil {
+		location = time.UTC
+	}
+	var total time.Duration
+	from = from.In(location)
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !w.Days[day.Weekday()] {
+			continue
+		}
+		open, close := atClock(day, w.Start), atClock(day, w.End)
+		if open.Before(from) {
+			open = from
+		}
+		if close.After(to) {
+			close = to
+		}
+		if close.After(open) {
+			total += close.Sub(open)
+		}
+	}
+	return total
+}
+
+// ReviewReminderConfig configura los recordatorios de revisión pendiente.
+type ReviewReminderConfig struct {
+	Threshold      time.Duration            // Tiempo laboral sin actividad de revisión antes de recordar
+	RepoThresholds map[string]time.Duration // Umbral por repositorio ("owner/repo")
+	Calendar       WorkingHours
+	RepoCalendars  map[string]WorkingHours // Calendario por repositorio ("owner/repo")
+	SnoozeLabel    string                  // Los pull requests con esta etiqueta no se recuerdan; vacío = sin snooze
+}
+
+// WithReviewReminders sigue los pull requests abiertos y habilita el reporte ReviewReminderReport,
+// que recuerda a los revisores pedidos los que llevan más de config.Threshold de tiempo laboral
+// sin actividad de revisión. Cada pull request se vuelve a recordar tras otro umbral sin actividad.
+func WithReviewReminders(store PullRequestStore, config ReviewReminderConfig) ServiceOption {
+	return func(s *webhookService) {
+		s.openPullRequests = store
+		s.registerReport(ReviewReminderReport, func(ctx context.Context) error {
+			return s.publishReviewReminders(ctx, config)
+		})
+	}
+}
+
+// reviewActivityActions son las acciones de pull_request que reinician la espera de revisión.
+var reviewActivityActions = []string{"opened", "reopened", "ready_for_review", "review_requested"}
+
+// trackPullRequest actualiza el pull request seguido con una entrega de pull_request o
+// pull_request_review. Se llama para todas las acciones, tengan o no un manejador.
+// Los errores solo se registran: el seguimiento no debe impedir la notificación. Las vistas
+// previas y las reproducciones no se siguen: revivirían un pull request ya cerrado.
+func (s *webhookService) trackPullRequest(ctx context.Context, event Event) {
+	if s.openPullRequests == nil || (event.Name != "pull_request" && event.Name != "pull_request_review") {
+		return
+	}
+	if IsDryRun(ctx) || event.ReplayOf != "" {
+		return
+	}
+	var payload struct {
+		PullRequest domain.PullRequest `json:"pull_request"`
+		Repository  domain.Repository  `json:"repository"`
+		Review      *domain.Review     `json:"review"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil || payload.PullRequest.Number == 0 {
+		return // El manejador reporta el payload inválido
+	}
+	pr := payload.PullRequest
+	key := pullRequestKey(payload.Repository.FullName, pr.Number)
+	if event.Name == "pull_request" && event.Action == "closed" {
+		if err := s.openPullRequests.DeletePullRequest(ctx, key); err != nil {
+			Logger(ctx).Error("Deleting tracked pull request", "pr_key", key, "error", err)
+		}
+		return
+	}
+
+	tracked, found, err := s.openPullRequests.GetPullRequest(ctx, key)
+	if err != nil {
+		Logger(ctx).Error("Reading tracked pull request", "pr_key", key, "error", err)
+		return
+	}
+	// Cada payload trae el pull request completo: título, borrador, revisores y etiquetas al día
+	tracked.Repo, tracked.Number = payload.Repository.FullName, pr.Number
+	tracked.Title, tracked.URL, tracked.Author, tracked.Draft = pr.Title, pr.HTMLURL, pr.User.Login, pr.Draft
+	tracked.Reviewers, tracked.Teams, tracked.Labels = nil, nil, nil
+	for _, user := range pr.RequestedReviewers {
+		tracked.Reviewers = append(tracked.Reviewers, user.Login)
+	}
+	for _, team := range pr.RequestedTeams {
+		tracked.Teams = append(tracked.Teams, team.Slug)
+	}
+	for _, label := range pr.Labels {
+		tracked.Labels = append(tracked.Labels, label.Name)
+	}
+
+	// Sin seguimiento previo (abierto antes de que el servicio lo viera) la espera cuenta desde ahora
+	reset := !found
+	var activity *time.Time
+	switch {
+	case event.Name == "pull_request_review" && event.Action == "submitted" && payload.Review != nil:
+		activity, reset = payload.Review.SubmittedAt, true
+	case event.Name == "pull_request" && slices.Contains(reviewActivityActions, event.Action):
+		activity, reset = pr.UpdatedAt, true
+	}
+	if reset {
+		tracked.LastActivity = time.Now().UTC()
+		if activity != nil {
+			tracked.LastActivity = *activity
+		}
+		tracked.RemindedAt = time.Time{}
+	}
+	if err := s.openPullRequests.SavePullRequest(ctx, key, tracked); err != nil {
+		Logger(ctx).Error("Saving tracked pull request", "pr_key", key, "error", err)
+	}
+}
+
+// publishReviewReminders publica un recordatorio por cada pull request que lleva más del umbral
+// de su repositorio sin actividad de revisión. Los borradores, los pospuestos y los que no tienen
+// revisores ni equipos pendientes (ej: ya aprobados, esperando el merge) se omiten.
+func (s *webhookService) publishReviewReminders(ctx context.Context, config ReviewReminderConfig) error {
+	prs, err := s.openPullRequests.ListPullRequests(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tracked pull requests: %w", err)
+	}
+	sort.Slice(prs, func(i, j int) bool { return prs[i].LastActivity.Before(prs[j].LastActivity) })
+
+	now := time.Now().UTC()
+	var errs []error
+	for _, pr := range prs {
+		if pr.Draft || (len(pr.Reviewers) == 0 && len(pr.Teams) == 0) || (config.SnoozeLabel != "" && slices.ContainsFunc(pr.Labels, func(label string) bool {
+			return strings.EqualFold(label, config.SnoozeLabel)
+		})) {
+			continue
+		}
+		threshold, ok := config.RepoThresholds[pr.Repo]
+		if !ok {
+			threshold = config.Threshold
+		}
+		calendar, ok := config.RepoCalendars[pr.Repo]
+		if !ok {
+			calendar = config.Calendar
+		}
+		waiting := calendar.elapsed(pr.LastActivity, now)
+		// Tras un recordatorio, el siguiente espera otro umbral completo
+		since := pr.LastActivity
+		if pr.RemindedAt.After(since) {
+			since = pr.RemindedAt
+		}
+		if calendar.elapsed(since, now) < threshold {
+			continue
+		}
+
+		key := pullRequestKey(pr.Repo, pr.Number)
+		payload := s.reviewReminderPayload(pr, calendar, waiting, config.SnoozeLabel)
+		if err := s.notifyPullRequest(ctx, pullRequestChannel, pr.Repo, pr.Number, pr.Title, payload); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		Logger(ctx).Info("Sent review reminder", "pr_key", key)
+		pr.RemindedAt = now
+		if err := s.openPullRequests.SavePullRequest(ctx, key, pr); err != nil {
+			Logger(ctx).Error("Saving tracked pull request", "pr_key", key, "error", err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reviewReminderPayload arma el recordatorio "⏰ Waiting for Review", que menciona a los revisores pedidos.
+func (s *webhookService) reviewReminderPayload(pr TrackedPullRequest, calendar WorkingHours, waiting time.Duration, snoozeLabel string) DiscordPayload {
+	idle := formatAge(waiting)
+	if slices.Contains(calendar.Days[:], true) {
+		idle = fmt.Sprintf("%d working hours", int(waiting.Hours()))
+	}
+	var pings mentionList
+	var names []string
+	for _, login := range pr.Reviewers {
+		names = append(names, login)
+		s.mentionUser(&pings, login)
+	}
+	for _, slug := range pr.Teams {
+		names = append(names, slug+" (team)")
+		s.mentionTeam(&pings, repoOwner(pr.Repo), slug)
+	}
+	reviewers := truncate(strings.Join(names, ", "), maxFieldValueLength)
+
+	embed := DiscordEmbed{
+		Title:       fmt.Sprintf("⏰ Waiting for Review #%d: %s", pr.Number, pr.Title),
+		Description: fmt.Sprintf("No review activity in %s for **%s**.", pr.Repo, idle),
+		URL:         pr.URL,
+		Color:       15105570, // Naranja
+		Fields: []DiscordField{
+			{Name: "Author", Value: pr.Author, Inline: true},
+			{Name: "Reviewers", Value: reviewers, Inline: true},
+			{Name: "Last Activity", Value: fmt.Sprintf("<t:%d:R>", pr.LastActivity.Unix()), Inline: true},
+		},
+	}
+	if snoozeLabel != "" {
+		embed.Footer = &DiscordFooter{Text: fmt.Sprintf("Add the %q label to snooze these reminders", snoozeLabel)}
+	}
+	payload := DiscordPayload{Embeds: []DiscordEmbed{embed}}
+	pings.apply(&payload, "this pull request is waiting for your review")
+	return payload
+}
+
+// pullRequestKey identifica un pull request seguido (ej: "octo-org/hello-world#42").
+func pullRequestKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
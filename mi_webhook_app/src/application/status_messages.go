@@ -0,0 +1,125 @@
+// File: src/application/status_messages.go
+package application
+
+import (
+	"context"
+	"sync"
+)
+
+// WithStatusMessages guarda el mensaje publicado para cada estado editable (ej: un workflow run)
+// para que los eventos siguientes lo editen en lugar de publicar uno nuevo.
+func WithStatusMessages(store StatusMessageStore) ServiceOption {
+	return func(s *webhookService) {
+		s.statusMessages = store
+	}
+}
+
+// publishStatus publica el mensaje de estado de key o, si ya existe, lo edita.
+// final indica el último estado: el mensaje se olvida después de editarlo.
+// Si el evento pertenece a un pull request (number > 0) se publica en su hilo.
+func (s *webhookService) publishStatus(ctx context.Context, channelType, key, repo string, number int, payload DiscordPayload, final bool) error {
+	destination := resolveDestination(ctx, channelType)
+	key = destination + "/" + key
+	ctx = WithLogAttrs(ctx, "status_key", key)
+	// Los eventos de un mismo run pueden llegar casi a la vez; sin el lock ambos publicarían
+	unlock := s.lockStatus(key)
+	defer unlock()
+
+	if stored, found := s.statusMessage(ctx, key); found {
+		err := s.edit(ctx, stored, payload)
+		if err == nil {
+			// Una edición en dry-run no tocó el mensaje: el estado real todavía debe editarlo
+			if final && !s.isDryRun(ctx, stored.Destination) {
+				s.forgetStatusMessage(ctx, key)
+			}
+			return s.pingAfterEdit(ctx, stored, payload)
+		}
+		if !final {
+			return err
+		}
+		// El estado final no debe perderse (ej: el mensaje fue borrado): se publica uno nuevo
+		Logger(ctx).Warn("Editing status message failed, posting a new one", "error", err)
+		s.forgetStatusMessage(ctx, key)
+	}
+
+	if !final && s.statusMessages != nil {
+		ctx = withoutCoalescing(ctx) // El mensaje se edita después: se necesita su ID
+	}
+	message, threadID, err := s.sendPullRequest(ctx, channelType, repo, number, "", payload)
+	if err != nil || final || message == nil || s.statusMessages == nil {
+		return err
+	}
+	stored := StatusMessage{Destination: destination, MessageID: message.ID, ThreadID: threadID}
+	if err := s.statusMessages.SaveStatusMessage(ctx, key, stored); err != nil {
+		Logger(ctx).Error("Saving status message", "error", err)
+	}
+	return nil
+}
+
+// pingAfterEdit publica aparte las menciones de un mensaje editado: Discord no notifica
+// las menciones que aparecen al editar.
+func (s *webhookService) pingAfterEdit(ctx context.Context, stored StatusMessage, payload DiscordPayload) error {
+	if payload.Content == "" || payload.AllowedMentions == nil {
+		return nil
+	}
+	ping := DiscordPayload{Content: payload.Content, AllowedMentions: payload.AllowedMentions, ThreadID: stored.ThreadID}
+	_, err := s.send(ctx, stored.Destination, ping)
+	return err
+}
+
+// hasStatusMessage indica si hay un mensaje de estado publicado para key en el destino.
+func (s *webhookService) hasStatusMessage(ctx context.Context, channelType, key string) bool {
+	_, found := s.statusMessage(ctx, resolveDestination(ctx, channelType)+"/"+key)
+	return found
+}
+
+// statusMessage lee el mensaje guardado; un error de lectura se trata como inexistente.
+func (s *webhookService) statusMessage(ctx context.Context, key string) (StatusMessage, bool) {
+	if s.statusMessages == nil {
+		return StatusMessage{}, false
+	}
+	stored, found, err := s.statusMessages.GetStatusMessage(ctx, key)
+	if err != nil {
+		Logger(ctx).Error("Reading status message", "error", err)
+		return StatusMessage{}, false
+	}
+	return stored, found
+}
+
+func (s *webhookService) forgetStatusMessage(ctx context.Context, key string) {
+	if err := s.statusMessages.DeleteStatusMessage(ctx, key); err != nil {
+		Logger(ctx).Error("Deleting status message", "error", err)
+	}
+}
+
+// statusLock es el mutex de una clave y cuántos eventos lo tienen o lo esperan.
+type statusLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockStatus serializa los eventos de una misma clave. La entrada se borra cuando ningún
+// evento la tiene ni la espera: borrarla antes dejaría entrar a otro con un mutex nuevo.
+func (s *webhookService) lockStatus(key string) func() {
+	s.statusLocksMu.Lock()
+	if s.statusLocks == nil {
+		s.statusLocks = make(map[string]*statusLock)
+	}
+	lock, ok := s.statusLocks[key]
+	if !ok {
+		lock = &statusLock{}
+		s.statusLocks[key] = lock
+	}
+	lock.refs++
+	s.statusLocksMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		s.statusLocksMu.Lock()
+		defer s.statusLocksMu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(s.statusLocks, key)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// File: src/application/tracing.go
+package application
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Atributos de span compartidos por los adaptadores y el servicio.
+const (
+	AttrEvent       = attribute.Key("github.event")
+	AttrAction      = attribute.Key("github.action")
+	AttrRepository  = attribute.Key("github.repository")
+	AttrDeliveryID  = attribute.Key("github.delivery_id")
+	AttrDestination = attribute.Key("notification.destination")
+)
+
+// tracer usa el TracerProvider global; es no-op si no se configuró un exportador.
+var tracer = otel.Tracer("mi_webhook_app/application")
+
+// startSpan inicia un span hijo del span del contexto.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan marca el span como fallido si hubo error y lo cierra.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -1,221 +1,263 @@
-// File: src/application/webhook_service.go
-package application
-
-import (
-	"encoding/json"
-	"fmt"
-	"log"
-	"time" // Importa time
-
-	// --- IMPORTACIÓN ACTUALIZADA (usa tu nombre de módulo) ---
-	domain "mi_webhook_app/src/domain/value_objects"
-	// --- FIN IMPORTACIÓN ACTUALIZADA ---
-)
-
-// webhookService implementa la interfaz WebhookProcessor.
-type webhookService struct {
-	// Depende del puerto NotificationService (interfaz), no de una implementación concreta.
-	notifier NotificationService
-}
-
-// NewWebhookService es el constructor para webhookService.
-// Recibe la implementación concreta del notificador a través de la interfaz.
-func NewWebhookService(notifier NotificationService) WebhookProcessor {
-	return &webhookService{
-		notifier: notifier,
-	}
-}
-
-// ProcessPullRequestEvent maneja eventos pull_request. Implementa WebhookProcessor.
-func (s *webhookService) ProcessPullRequestEvent(payload []byte) error {
-	var event domain.PullRequestEventPayload
-	if err := json.Unmarshal(payload, &event); err != nil {
-		log.Printf("ERROR: Unmarshalling PullRequestEventPayload: %v", err)
-		return fmt.Errorf("failed to unmarshal pull request payload: %w", err)
-	}
-
-	var messageEmbed DiscordEmbed
-	var sendMessage bool = true // Flag para controlar el envío
-	var sendErr error = nil    // Para capturar errores potenciales de notificación
-
-	pr := event.PullRequest
-	repo := event.Repository
-	sender := event.Sender
-
-	// Footer y Timestamp por defecto (pueden ser sobreescritos)
-	footer := &DiscordFooter{Text: fmt.Sprintf("Triggered by %s", sender.Login)}
-	timestamp := time.Now().Format(time.RFC3339) // Usa tiempo actual por defecto
-
-	switch event.Action {
-	case "opened":
-		if pr.CreatedAt != nil { // Verifica si CreatedAt está disponible
-			timestamp = pr.CreatedAt.Format(time.RFC3339)
-		}
-		messageEmbed = DiscordEmbed{
-			Title:       fmt.Sprintf("🚀 New Pull Request #%d: %s", event.Number, pr.Title),
-			Description: fmt.Sprintf("A new pull request was opened in [%s](%s).", repo.FullName, repo.HTMLURL),
-			URL:         pr.HTMLURL,
-			Color:       3447003, // Azul
-			Fields: []DiscordField{
-				{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
-				{Name: "Branch", Value: fmt.Sprintf("`%s` → `%s`", pr.Head.Ref, pr.Base.Ref), Inline: true},
-			},
-			Footer:    footer,
-			Timestamp: timestamp,
-		}
-	case "reopened":
-		if pr.UpdatedAt != nil { // Verifica si UpdatedAt está disponible
-			timestamp = pr.UpdatedAt.Format(time.RFC3339)
-		}
-		footer.Text = fmt.Sprintf("Reopened by %s", sender.Login)
-		messageEmbed = DiscordEmbed{
-			Title:       fmt.Sprintf("🔄 Pull Request Reopened #%d: %s", event.Number, pr.Title),
-			Description: fmt.Sprintf("Pull request reopened in [%s](%s).", repo.FullName, repo.HTMLURL),
-			URL:         pr.HTMLURL,
-			Color:       16776960, // Amarillo
-			Fields: []DiscordField{
-				{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
-				{Name: "Branch", Value: fmt.Sprintf("`%s` → `%s`", pr.Head.Ref, pr.Base.Ref), Inline: true},
-			},
-			Footer:    footer,
-			Timestamp: timestamp,
-		}
-	case "ready_for_review":
-		if pr.UpdatedAt != nil {
-			timestamp = pr.UpdatedAt.Format(time.RFC3339)
-		}
-		footer.Text = fmt.Sprintf("Marked ready by %s", sender.Login)
-		messageEmbed = DiscordEmbed{
-			Title:       fmt.Sprintf("👀 PR Ready for Review #%d: %s", event.Number, pr.Title),
-			Description: fmt.Sprintf("Pull request marked as ready for review in [%s](%s).", repo.FullName, repo.HTMLURL),
-			URL:         pr.HTMLURL,
-			Color:       3066993, // Verde
-			Fields: []DiscordField{
-				{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
-				{Name: "Branch", Value: fmt.Sprintf("`%s` → `%s`", pr.Head.Ref, pr.Base.Ref), Inline: true},
-			},
-			Footer:    footer,
-			Timestamp: timestamp,
-		}
-	case "closed":
-		if pr.Merged {
-			if pr.MergedAt != nil { // Verifica si MergedAt está disponible
-				timestamp = pr.MergedAt.Format(time.RFC3339)
-			}
-			footer.Text = "Merged"
-			messageEmbed = DiscordEmbed{
-				Title:       fmt.Sprintf("✅ Pull Request Merged #%d: %s", event.Number, pr.Title),
-				Description: fmt.Sprintf("Pull request successfully merged into `%s` in [%s](%s).", pr.Base.Ref, repo.FullName, repo.HTMLURL),
-				URL:         pr.HTMLURL,
-				Color:       8359053, // Púrpura
-				Fields: []DiscordField{
-					{Name: "Author", Value: fmt.Sprintf("[%s](%s)", pr.User.Login, pr.User.HTMLURL), Inline: true},
-					{Name: "Merged By", Value: fmt.Sprintf("[%s](%s)", sender.Login, sender.HTMLURL), Inline: true}, // Asume que sender es quien hizo merge
-				},
-				Footer:    footer,
-				Timestamp: timestamp,
-			}
-		} else {
-			log.Printf("INFO: Pull Request #%d closed without merging (Action: %s). No notification sent.", event.Number, event.Action)
-			sendMessage = false
-		}
-	default:
-		log.Printf("INFO: Unhandled Pull Request Action: %s for PR #%d. No notification sent.", event.Action, event.Number)
-		sendMessage = false
-	}
-
-	if sendMessage {
-		log.Printf("INFO: Sending Pull Request notification to Development channel for action: %s", event.Action)
-		discordPayload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
-		// Usa el notificador inyectado a través del puerto de interfaz
-		err := s.notifier.SendNotification("development", discordPayload)
-		if err != nil {
-			log.Printf("ERROR: Sending development notification: %v", err)
-			// Decide cómo manejar errores de notificación. Lo retornamos aquí.
-			sendErr = fmt.Errorf("failed to send development notification: %w", err)
-		}
-	}
-
-	return sendErr // Retorna nil si fue exitoso o si no se envió mensaje intencionalmente
-}
-
-// ProcessWorkflowRunEvent maneja eventos workflow_run. Implementa WebhookProcessor.
-func (s *webhookService) ProcessWorkflowRunEvent(payload []byte) error {
-	var event domain.WorkflowRunEventPayload
-	if err := json.Unmarshal(payload, &event); err != nil {
-		log.Printf("ERROR: Unmarshalling WorkflowRunEventPayload: %v", err)
-		return fmt.Errorf("failed to unmarshal workflow run payload: %w", err)
-	}
-
-	// Solo nos interesan workflows completados
-	if event.Action != "completed" {
-		log.Printf("INFO: Ignoring workflow_run event with action '%s'", event.Action)
-		return nil // No es un error, solo ignorado
-	}
-
-	run := event.WorkflowRun
-	repo := event.Repository
-	workflow := event.Workflow
-
-	var messageEmbed DiscordEmbed
-	var color int
-	var statusEmoji string
-	var sendMessage bool = true
-	var sendErr error = nil
-
-	switch run.Conclusion {
-	case "success":
-		color = 3066993
-		statusEmoji = "✅"
-	case "failure":
-		color = 15158332
-		statusEmoji = "❌"
-	case "cancelled":
-		color = 9807270
-		statusEmoji = "⏹️"
-	case "skipped":
-		color = 16776960
-		statusEmoji = "⏭️"
-	default:
-		log.Printf("INFO: Unhandled Workflow Conclusion: %s for Run ID %d. No notification sent.", run.Conclusion, run.ID)
-		sendMessage = false
-	}
-
-	if sendMessage {
-		// Construye descripción, menciona PR si está disponible
-		description := fmt.Sprintf("Workflow **%s** completed with status: **%s**", workflow.Name, run.Conclusion)
-		if len(run.PullRequests) > 0 {
-			prNumber := run.PullRequests[0].Number
-			prURL := fmt.Sprintf("%s/pull/%d", repo.HTMLURL, prNumber)
-			description += fmt.Sprintf("\nAssociated Pull Request: [#%d](%s)", prNumber, prURL)
-		}
-
-		timestamp := run.UpdatedAt.Format(time.RFC3339) // Usa tiempo de completado
-
-		messageEmbed = DiscordEmbed{
-			Title:       fmt.Sprintf("%s Workflow Run %s: %s", statusEmoji, run.Conclusion, workflow.Name),
-			Description: description,
-			URL:         run.HTMLURL, // Enlace a la ejecución específica
-			Color:       color,
-			Fields: []DiscordField{
-				{Name: "Repository", Value: fmt.Sprintf("[%s](%s)", repo.FullName, repo.HTMLURL), Inline: true},
-				{Name: "Branch", Value: fmt.Sprintf("`%s`", run.HeadBranch), Inline: true},
-				{Name: "Triggered By", Value: fmt.Sprintf("[%s](%s)", event.Sender.Login, event.Sender.HTMLURL), Inline: true},
-				{Name: "Event", Value: run.Event, Inline: true},
-				{Name: "Run ID", Value: fmt.Sprintf("[%d](%s)", run.ID, run.HTMLURL), Inline: true},
-			},
-			Footer:    &DiscordFooter{Text: fmt.Sprintf("Workflow: %s", workflow.Path)},
-			Timestamp: timestamp,
-		}
-
-		log.Printf("INFO: Sending Workflow Run notification to Testing channel (Conclusion: %s)", run.Conclusion)
-		discordPayload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
-		// Usa el notificador inyectado a través del puerto de interfaz
-		err := s.notifier.SendNotification("testing", discordPayload)
-		if err != nil {
-			log.Printf("ERROR: Sending testing notification: %v", err)
-			sendErr = fmt.Errorf("failed to send testing notification: %w", err)
-		}
-	}
-	return sendErr
-}
\ No newline at end of file
+// File: src/application/webhook_service.go
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// webhookService implementa la interfaz WebhookProcessor.
+type webhookService struct {
+	// Depende del puerto NotificationService (interfaz), no de una implementación concreta.
+	notifier NotificationService
+	// registry contiene los manejadores registrados por cada módulo de evento.
+	registry *EventRegistry
+	// deliveries es el historial de entregas (opcional).
+	deliveries    DeliveryStore
+	storePayloads bool
+	// dryRunAll y dryRunDestinations registran notificaciones sin publicarlas.
+	dryRunAll          bool
+	dryRunDestinations map[string]bool
+	// threads recuerda el hilo de cada pull request en los destinos de threadDestinations.
+	threads            ThreadStore
+	threadDestinations map[string]bool
+	// statusMessages recuerda los mensajes que se editan al cambiar de estado.
+	statusMessages StatusMessageStore
+	statusLocksMu  sync.Mutex
+	statusLocks    map[string]*statusLock // Solo las claves con eventos en curso
+	// discordUsers y discordRoles mapean usuarios y equipos de GitHub a menciones de Discord.
+	discordUsers map[string]string
+	discordRoles map[string]string
+	// changedFiles y codeOwners resuelven los dueños de los archivos de un PR (opcionales).
+	changedFiles ChangedFilesSource
+	codeOwners   CodeOwnersSource
+	// github enriquece las notificaciones con datos de la API (opcional).
+	github GitHubClient
+	// runHistory guarda los resultados de las ejecuciones para detectar workflows inestables (opcional).
+	runHistory     RunHistoryStore
+	flakyWindow    time.Duration
+	flakyThreshold int // Porcentaje de flip rate desde el que una falla se marca como "probablemente flaky"
+	// branchStates sigue si cada workflow está roto en la rama principal (opcional).
+	branchStates             BranchStateStore
+	suppressRepeatedFailures bool
+	// openPullRequests sigue los pull requests abiertos para recordar las revisiones pendientes (opcional).
+	openPullRequests PullRequestStore
+	// deployEnvironments son los entornos que cuentan en las métricas de deployments (vacío = todos).
+	deployEnvironments []string
+	// heldNotifications y quietHours silencian destinos o rutas en ciertos horarios (opcional).
+	heldNotifications HeldNotificationStore
+	quietHours        map[string]QuietHours
+	// reports son los reportes periódicos habilitados, por nombre.
+	reports map[string]func(ctx context.Context) error
+	// jobLogLines es el largo del extracto de log en las ejecuciones fallidas (0 = sin extracto).
+	jobLogLines int
+	// installations registra qué repositorios cubre cada instalación de la GitHub App (opcional).
+	installations InstallationStore
+}
+
+// NewWebhookService es el constructor para webhookService.
+// Recibe la implementación concreta del notificador a través de la interfaz
+// y registra los manejadores de todos los módulos de evento conocidos.
+// Las dependencias opcionales (ej: historial de entregas) se pasan como ServiceOption.
+func NewWebhookService(notifier NotificationService, opts ...ServiceOption) WebhookService {
+	s := &webhookService{
+		notifier: notifier,
+		registry: NewEventRegistry(),
+		reports:  make(map[string]func(ctx context.Context) error),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for _, module := range eventModules {
+		module(s)
+	}
+	return s
+}
+
+// Process despacha el evento al manejador registrado para su par (evento, acción).
+// Implementa WebhookProcessor. Retorna ErrEventNotHandled si no hay manejador.
+// Si hay historial configurado, cada entrega queda registrada con su resultado y las que ya
+// se procesaron retornan ErrDuplicateDelivery sin procesarse de nuevo.
+func (s *webhookService) Process(ctx context.Context, event Event) error {
+	var envelope struct {
+		Action     string `json:"action"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Sender struct {
+			Login string `json:"login"`
+		} `json:"sender"`
+		Installation struct {
+			ID int64 `json:"id"`
+		} `json:"installation"`
+	}
+	if err := json.Unmarshal(event.Payload, &envelope); err != nil {
+		// No es fatal aquí: si hay un manejador para el evento, él reportará el error al decodificar.
+		Logger(ctx).Warn("Unmarshalling payload envelope", "error", err)
+	}
+	if event.Action == "" {
+		event.Action = envelope.Action
+		ctx = WithLogAttrs(ctx, "action", event.Action)
+	}
+	ctx = WithLogAttrs(ctx, "repo", envelope.Repository.FullName)
+	if envelope.Installation.ID != 0 {
+		// Las consultas a la API durante esta entrega usan el token de esta instalación
+		ctx = WithInstallationID(ctx, envelope.Installation.ID)
+	}
+	// El span de la entrega (creado por el adaptador) también lleva el repositorio
+	trace.SpanFromContext(ctx).SetAttributes(AttrRepository.String(envelope.Repository.FullName))
+
+	if s.isDuplicate(ctx, event) {
+		Logger(ctx).Info("Skipping delivery already processed")
+		return ErrDuplicateDelivery
+	}
+	record := newDeliveryRecord(event, envelope.Repository.FullName, envelope.Sender.Login)
+	ctx = withDeliveryDeadline(ctx, time.Now().Add(deliveryBudget))
+	if event.ReplayOf != "" {
+		ctx = context.WithValue(ctx, replayContextKey{}, true)
+	}
+	ctx, capture := captureFrom(ctx)
+	if key := coalesceKey(envelope.Repository.FullName, event.Payload); key != "" {
+		// El notificador puede agrupar los envíos del mismo pull request o commit
+		ctx = WithCoalesceKey(ctx, key)
+	}
+	s.trackPullRequest(ctx, event)
+	err := s.route(ctx, event, envelope.Repository.FullName)
+	s.saveDelivery(ctx, record, event.Payload, err, capture)
+	return err
+}
+
+// replayContextKey marca en el contexto que la entrega en curso es una reproducción.
+type replayContextKey struct{}
+
+// isReplay indica si la entrega en curso reproduce una del historial: sus efectos ya se
+// registraron cuando llegó la original y no deben guardarse de nuevo.
+func isReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayContextKey{}).(bool)
+	return replay
+}
+
+// deliveryDeadlineContextKey es la clave de contexto del plazo de la entrega en curso.
+type deliveryDeadlineContextKey struct{}
+
+// withDeliveryDeadline fija el plazo de la entrega; si ctx ya vence antes, se usa ese.
+// No cancela nada: los pasos opcionales (ej: adjuntar el log) lo consultan para omitirse.
+func withDeliveryDeadline(ctx context.Context, deadline time.Time) context.Context {
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	return context.WithValue(ctx, deliveryDeadlineContextKey{}, deadline)
+}
+
+// deliveryTimeLeft retorna cuánto queda del plazo de la entrega; false si no hay plazo (ej: reportes).
+func deliveryTimeLeft(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Value(deliveryDeadlineContextKey{}).(time.Time)
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// route busca y ejecuta el manejador del evento dentro del span de enrutamiento.
+func (s *webhookService) route(ctx context.Context, event Event, repo string) error {
+	ctx, span := startSpan(ctx, "webhook.route",
+		AttrEvent.String(event.Name), AttrAction.String(event.Action), AttrRepository.String(repo))
+	_, capture := captureFrom(ctx)
+	route, handler, ok := s.registry.Lookup(event.Name, event.Action)
+	capture.setRoute(route, ok)
+	if !ok {
+		Logger(ctx).Info("No handler registered for event, no notification sent")
+		span.SetAttributes(attribute.Bool("webhook.handled", false))
+		span.End()
+		return ErrEventNotHandled
+	}
+	span.SetAttributes(attribute.Bool("webhook.handled", true))
+	err := handler(ctx, event)
+	endSpan(span, err)
+	return err
+}
+
+// notify envía un payload al canal lógico indicado y envuelve el error para el llamador.
+func (s *webhookService) notify(ctx context.Context, channelType string, payload DiscordPayload) error {
+	_, err := s.send(ctx, channelType, payload)
+	return err
+}
+
+// send publica el payload y retorna el mensaje creado (nil en dry-run o en horario de silencio).
+// Si el contexto trae un destino forzado (ej: una reproducción), se usa ese en lugar del canal del evento.
+func (s *webhookService) send(ctx context.Context, channelType string, payload DiscordPayload) (*DiscordMessage, error) {
+	if resolved := resolveDestination(ctx, channelType); resolved != channelType {
+		Logger(ctx).Info("Redirecting notification", "original_destination", channelType)
+		channelType = resolved
+	}
+	ctx = WithLogAttrs(ctx, "destination", channelType)
+	restrictMentions(&payload)
+	if s.isDryRun(ctx, channelType) {
+		// Dry-run: se registra el payload renderizado y no se publica nada
+		Logger(ctx).Info("Dry-run: notification rendered but not sent")
+		recordNotification(ctx, NotificationRecord{Destination: channelType, DryRun: true, Payload: &payload}, nil)
+		return nil, nil
+	}
+	if window, releaseAt, quiet := s.quietWindow(ctx, channelType, time.Now()); quiet && !payload.Critical {
+		return nil, s.holdNotification(ctx, channelType, payload, window, releaseAt)
+	}
+	Logger(ctx).Info("Sending notification")
+	// Usa el notificador inyectado a través del puerto de interfaz
+	message, err := s.notifier.SendNotification(ctx, channelType, payload)
+	recordNotification(ctx, NotificationRecord{Destination: channelType}, err)
+	if err != nil {
+		Logger(ctx).Error("Sending notification failed", "error", err)
+		return nil, fmt.Errorf("failed to send %s notification: %w", channelType, err)
+	}
+	return message, nil
+}
+
+// edit reemplaza un mensaje publicado antes. En dry-run solo se registra el payload renderizado.
+func (s *webhookService) edit(ctx context.Context, message StatusMessage, payload DiscordPayload) error {
+	ctx = WithLogAttrs(ctx, "destination", message.Destination, "message_id", message.MessageID)
+	record := NotificationRecord{Destination: message.Destination, MessageID: message.MessageID}
+	restrictMentions(&payload)
+	if s.isDryRun(ctx, message.Destination) {
+		Logger(ctx).Info("Dry-run: message edit rendered but not sent")
+		record.DryRun, record.Payload = true, &payload
+		recordNotification(ctx, record, nil)
+		return nil
+	}
+	Logger(ctx).Info("Editing notification")
+	payload.ThreadID = message.ThreadID
+	_, err := s.notifier.EditNotification(ctx, message.Destination, message.MessageID, payload)
+	recordNotification(ctx, record, err)
+	if err != nil {
+		Logger(ctx).Error("Editing notification failed", "error", err)
+		return fmt.Errorf("failed to edit %s notification: %w", message.Destination, err)
+	}
+	return nil
+}
+
+// resolveDestination aplica el destino forzado del contexto, si existe.
+func resolveDestination(ctx context.Context, channelType string) string {
+	if override, ok := destinationOverride(ctx); ok {
+		return override
+	}
+	return channelType
+}
+
+// destinationOverrideKey es la clave de contexto del destino forzado.
+type destinationOverrideKey struct{}
+
+// WithDestinationOverride envía todas las notificaciones de la entrega al canal indicado,
+// sin importar a qué canal las enrutaría su manejador.
+func WithDestinationOverride(ctx context.Context, channelType string) context.Context {
+	return context.WithValue(ctx, destinationOverrideKey{}, channelType)
+}
+
+func destinationOverride(ctx context.Context) (string, bool) {
+	channelType, ok := ctx.Value(destinationOverrideKey{}).(string)
+	return channelType, ok && channelType != ""
+}
@@ -0,0 +1,1249 @@
+// File: src/application/webhook_service_test.go
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mi_webhook_app/src/application"
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// update regenera los archivos golden: go test ./src/application -update
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// sentNotification es un envío capturado por capturingNotifier.
+type sentNotification struct {
+	Destination string                     `json:"destination"`
+	MessageID   string                     `json:"message_id,omitempty"` // Solo en ediciones
+	Payload     application.DiscordPayload `json:"payload"`
+}
+
+// capturingNotifier implementa application.NotificationService guardando los envíos en memoria.
+type capturingNotifier struct {
+	mu   sync.Mutex
+	sent []sentNotification
+	err  error // Si no es nil, todos los envíos fallan con este error
+}
+
+func (n *capturingNotifier) SendNotification(_ context.Context, channelType string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, sentNotification{Destination: channelType, Payload: payload})
+	if n.err != nil {
+		return nil, n.err
+	}
+	// Simula Discord: un mensaje que abre un hilo vive en un canal nuevo
+	message := &application.DiscordMessage{ID: fmt.Sprintf("m%d", len(n.sent)), ChannelID: "channel-" + channelType}
+	if payload.ThreadName != "" {
+		message.ChannelID = fmt.Sprintf("thread-%d", len(n.sent))
+	}
+	return message, nil
+}
+
+func (n *capturingNotifier) EditNotification(_ context.Context, channelType, messageID string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, sentNotification{Destination: channelType, MessageID: messageID, Payload: payload})
+	if n.err != nil {
+		return nil, n.err
+	}
+	return &application.DiscordMessage{ID: messageID, ChannelID: payload.ThreadID}, nil
+}
+
+// goldenResult es lo que se compara contra testdata/golden/<fixture>.json.
+type goldenResult struct {
+	Handled       bool               `json:"handled"`
+	Route         string             `json:"route"`
+	Notifications []sentNotification `json:"notifications"`
+}
+
+// TestGoldenFixtures procesa cada payload de testdata/fixtures y compara lo enviado
+// con su archivo golden. El evento se toma del nombre del archivo (<evento>.<acción>[.<variante>].json).
+func TestGoldenFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found in testdata/fixtures")
+	}
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			payload, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notifier := &capturingNotifier{}
+			service := application.NewWebhookService(notifier)
+
+			ctx, capture := application.WithNotificationCapture(context.Background())
+			event := application.Event{Name: strings.Split(name, ".")[0], DeliveryID: "test-" + name, Payload: payload}
+			if err := service.Process(ctx, event); err != nil && !errors.Is(err, application.ErrEventNotHandled) {
+				t.Fatalf("Process returned error: %v", err)
+			}
+
+			route, handled := capture.Route()
+			result := goldenResult{Handled: handled, Route: route, Notifications: notifier.sent}
+			if result.Notifications == nil {
+				result.Notifications = []sentNotification{}
+			}
+			assertGolden(t, filepath.Join("testdata", "golden", name+".json"), result)
+		})
+	}
+}
+
+// TestProcessNotificationError verifica que un fallo del notificador se propaga al llamador.
+func TestProcessNotificationError(t *testing.T) {
+	payload, err := os.ReadFile(filepath.Join("testdata", "fixtures", "pull_request.opened.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := &capturingNotifier{err: errors.New("discord unavailable")}
+	service := application.NewWebhookService(notifier)
+
+	err = service.Process(context.Background(), application.Event{Name: "pull_request", Payload: payload})
+	if err == nil || !strings.Contains(err.Error(), "discord unavailable") {
+		t.Fatalf("expected notifier error to be returned, got %v", err)
+	}
+}
+
+// TestProcessUnknownEvent verifica que un evento sin manejador retorna ErrEventNotHandled.
+func TestProcessUnknownEvent(t *testing.T) {
+	service := application.NewWebhookService(&capturingNotifier{})
+	err := service.Process(context.Background(), application.Event{Name: "star", Payload: []byte(`{"action":"created"}`)})
+	if !errors.Is(err, application.ErrEventNotHandled) {
+		t.Fatalf("expected ErrEventNotHandled, got %v", err)
+	}
+}
+
+// memoryThreadStore implementa application.ThreadStore en memoria.
+type memoryThreadStore map[string]string
+
+func (m memoryThreadStore) GetThread(_ context.Context, key string) (string, bool, error) {
+	threadID, ok := m[key]
+	return threadID, ok, nil
+}
+
+func (m memoryThreadStore) SaveThread(_ context.Context, key, threadID string) error {
+	m[key] = threadID
+	return nil
+}
+
+// TestPullRequestThreads verifica que el primer evento de un PR abre un hilo y los siguientes
+// (revisiones, CI, merge) publican en él, y que el dry-run no deja hilos registrados.
+func TestPullRequestThreads(t *testing.T) {
+	notifier := &capturingNotifier{}
+	threads := memoryThreadStore{}
+	service := application.NewWebhookService(notifier,
+		application.WithPullRequestThreads(threads, "development", "testing"))
+
+	dryRun := application.WithDryRun(context.Background())
+	if err := service.Process(dryRun, application.Event{Name: "pull_request", Payload: readFixture(t, "pull_request.opened")}); err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 0 {
+		t.Fatalf("dry-run must not create threads, got %v", threads)
+	}
+
+	for _, fixture := range []string{"pull_request.opened", "pull_request_review.submitted.approved", "workflow_run.completed.failure", "pull_request.closed.merged"} {
+		event := application.Event{Name: strings.Split(fixture, ".")[0], Payload: readFixture(t, fixture)}
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+	}
+
+	if len(notifier.sent) != 4 {
+		t.Fatalf("expected 4 notifications, got %d", len(notifier.sent))
+	}
+	if first := notifier.sent[0].Payload; first.ThreadName != "#42 Add retry with backoff to the Discord notifier" || first.ThreadID != "" {
+		t.Errorf("first notification should open the thread, got name %q id %q", first.ThreadName, first.ThreadID)
+	}
+	for i, index := range []int{1, 3} {
+		if got := notifier.sent[index].Payload; got.ThreadID != "thread-1" || got.ThreadName != "" {
+			t.Errorf("notification %d should post into thread-1, got name %q id %q", i, got.ThreadName, got.ThreadID)
+		}
+	}
+	// El CI va a otro destino: abre su propio hilo allí
+	if ci := notifier.sent[2].Payload; ci.ThreadName != "octo-org/hello-world#42" {
+		t.Errorf("workflow run should open a thread in testing, got name %q id %q", ci.ThreadName, ci.ThreadID)
+	}
+	if threads["development/octo-org/hello-world#42"] != "thread-1" || threads["testing/octo-org/hello-world#42"] != "thread-3" {
+		t.Errorf("unexpected thread mapping %v", threads)
+	}
+}
+
+// memoryStatusStore implementa application.StatusMessageStore en memoria.
+type memoryStatusStore map[string]application.StatusMessage
+
+func (m memoryStatusStore) GetStatusMessage(_ context.Context, key string) (application.StatusMessage, bool, error) {
+	message, ok := m[key]
+	return message, ok, nil
+}
+
+func (m memoryStatusStore) SaveStatusMessage(_ context.Context, key string, message application.StatusMessage) error {
+	m[key] = message
+	return nil
+}
+
+func (m memoryStatusStore) DeleteStatusMessage(_ context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+// TestWorkflowRunStatusMessage verifica que una ejecución publica un único mensaje al encolarse
+// y lo edita en los estados siguientes, dentro del hilo del PR cuando el destino usa hilos.
+func TestWorkflowRunStatusMessage(t *testing.T) {
+	notifier := &capturingNotifier{}
+	statuses := memoryStatusStore{}
+	service := application.NewWebhookService(notifier,
+		application.WithStatusMessages(statuses),
+		application.WithPullRequestThreads(memoryThreadStore{}, "testing"))
+
+	for _, fixture := range []string{"workflow_run.requested", "workflow_run.in_progress", "workflow_run.completed.failure"} {
+		if err := service.Process(context.Background(), application.Event{Name: "workflow_run", Payload: readFixture(t, fixture)}); err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+	}
+
+	if len(notifier.sent) != 3 {
+		t.Fatalf("expected 3 notifications, got %d", len(notifier.sent))
+	}
+	if first := notifier.sent[0]; first.MessageID != "" || first.Payload.ThreadName == "" {
+		t.Errorf("requested should post a new message opening the PR thread, got %+v", first)
+	}
+	for _, edit := range notifier.sent[1:] {
+		if edit.MessageID != "m1" || edit.Payload.ThreadID != "thread-1" {
+			t.Errorf("expected an edit of m1 in thread-1, got message %q thread %q", edit.MessageID, edit.Payload.ThreadID)
+		}
+	}
+	if final := notifier.sent[2].Payload.Embeds[0]; !strings.HasPrefix(final.Title, "❌") {
+		t.Errorf("final edit should carry the conclusion, got %q", final.Title)
+	}
+	if len(statuses) != 0 {
+		t.Errorf("completed runs should be forgotten, got %v", statuses)
+	}
+}
+
+// TestWorkflowRunStatusMessageDryRun verifica que la vista previa de un estado final no olvida
+// el mensaje publicado: la finalización real todavía lo edita.
+func TestWorkflowRunStatusMessageDryRun(t *testing.T) {
+	notifier := &capturingNotifier{}
+	statuses := memoryStatusStore{}
+	service := application.NewWebhookService(notifier, application.WithStatusMessages(statuses))
+
+	for _, step := range []struct {
+		fixture string
+		dryRun  bool
+	}{{"workflow_run.in_progress", false}, {"workflow_run.completed.failure", true}, {"workflow_run.completed.failure", false}} {
+		ctx := context.Background()
+		if step.dryRun {
+			ctx = application.WithDryRun(ctx)
+		}
+		if err := service.Process(ctx, application.Event{Name: "workflow_run", Payload: readFixture(t, step.fixture)}); err != nil {
+			t.Fatalf("%s: %v", step.fixture, err)
+		}
+	}
+
+	if len(notifier.sent) != 2 || notifier.sent[1].MessageID != "m1" {
+		t.Fatalf("expected the real completion to edit m1, got %+v", notifier.sent)
+	}
+	if len(statuses) != 0 {
+		t.Errorf("the real completion should forget the status message, got %v", statuses)
+	}
+}
+
+// keyRecordingNotifier guarda la clave de agrupación de cada envío.
+type keyRecordingNotifier struct {
+	capturingNotifier
+	keys []string
+}
+
+func (n *keyRecordingNotifier) SendNotification(ctx context.Context, channelType string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	n.keys = append(n.keys, application.CoalesceKey(ctx))
+	return n.capturingNotifier.SendNotification(ctx, channelType, payload)
+}
+
+// TestCoalesceKey verifica que los envíos de un pull request y de sus ejecuciones comparten la clave
+// de agrupación y que los que abren un hilo o un mensaje de estado no la llevan.
+func TestCoalesceKey(t *testing.T) {
+	notifier := &keyRecordingNotifier{}
+	service := application.NewWebhookService(notifier)
+	for _, fixture := range []string{"pull_request.opened", "workflow_run.completed.failure"} {
+		event := application.Event{Name: strings.Split(fixture, ".")[0], Payload: readFixture(t, fixture)}
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+	}
+	if want := []string{"octo-org/hello-world#42", "octo-org/hello-world#42"}; !slices.Equal(notifier.keys, want) {
+		t.Errorf("expected keys %q, got %q", want, notifier.keys)
+	}
+
+	notifier = &keyRecordingNotifier{}
+	service = application.NewWebhookService(notifier,
+		application.WithStatusMessages(memoryStatusStore{}),
+		application.WithPullRequestThreads(memoryThreadStore{}, "development"))
+	for _, event := range []application.Event{
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.opened")},
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.review_requested")},
+		{Name: "workflow_run", Payload: readFixture(t, "workflow_run.in_progress")},
+	} {
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// El hilo y el mensaje de estado necesitan el mensaje creado; el segundo envío del hilo se puede agrupar
+	if want := []string{"", "octo-org/hello-world#42", ""}; !slices.Equal(notifier.keys, want) {
+		t.Errorf("expected keys %q, got %q", want, notifier.keys)
+	}
+}
+
+// TestMentions verifica que los revisores y autores mapeados se mencionan y que
+// allowed_mentions autoriza solo esas menciones (y ninguna en los demás mensajes).
+func TestMentions(t *testing.T) {
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithMentions(
+		map[string]string{"hubot": "111", "OctoCat": "222"},
+		map[string]string{"octo-org/backend": "333"}))
+
+	for _, fixture := range []string{"pull_request.opened", "workflow_run.completed.failure", "pull_request.closed.merged"} {
+		event := application.Event{Name: strings.Split(fixture, ".")[0], Payload: readFixture(t, fixture)}
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+	}
+
+	for i, want := range []struct {
+		content string
+		allowed application.AllowedMentions
+	}{
+		{"<@111> <@&333> review requested", application.AllowedMentions{Parse: []string{}, Users: []string{"111"}, Roles: []string{"333"}}},
+		{"<@222> your workflow run failed", application.AllowedMentions{Parse: []string{}, Users: []string{"222"}}},
+		{"", application.AllowedMentions{Parse: []string{}}},
+	} {
+		got := notifier.sent[i].Payload
+		if got.Content != want.content {
+			t.Errorf("notification %d: content %q, want %q", i, got.Content, want.content)
+		}
+		gotAllowed, _ := json.Marshal(got.AllowedMentions)
+		wantAllowed, _ := json.Marshal(want.allowed)
+		if !bytes.Equal(gotAllowed, wantAllowed) {
+			t.Errorf("notification %d: allowed_mentions %s, want %s", i, gotAllowed, wantAllowed)
+		}
+	}
+}
+
+// staticChangedFiles implementa application.ChangedFilesSource con una lista fija.
+type staticChangedFiles []string
+
+func (f staticChangedFiles) ChangedFiles(context.Context, string, int) ([]string, error) {
+	return f, nil
+}
+
+// staticCodeOwners implementa application.CodeOwnersSource con un CODEOWNERS fijo.
+type staticCodeOwners string
+
+func (c staticCodeOwners) CodeOwners(context.Context, string) (*domain.CodeOwners, error) {
+	return domain.ParseCodeOwners(strings.NewReader(string(c)))
+}
+
+// TestCodeOwnerMentions verifica que los dueños de los archivos modificados se mencionan
+// junto a los revisores, sin repetir y sin mencionar al autor del PR.
+func TestCodeOwnerMentions(t *testing.T) {
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier,
+		application.WithMentions(
+			map[string]string{"hubot": "111", "octocat": "222", "monalisa": "444"},
+			map[string]string{"octo-org/backend": "333", "octo-org/docs": "555"}),
+		application.WithCodeOwners(
+			staticChangedFiles{"src/notifier.go", "docs/retries.md", "README.md"},
+			staticCodeOwners("* @octocat\n*.go @octo-org/backend @monalisa\n/docs/ @octo-org/docs ops@example.com\n")))
+
+	event := application.Event{Name: "pull_request", Payload: readFixture(t, "pull_request.opened")}
+	if err := service.Process(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	got := notifier.sent[0].Payload
+	if want := "<@111> <@444> <@&333> <@&555> review requested"; got.Content != want {
+		t.Errorf("content %q, want %q", got.Content, want)
+	}
+	fields := got.Embeds[0].Fields
+	if last := fields[len(fields)-1]; last.Name != "Code Owners" || last.Value != "@octo-org/backend, @monalisa, @octo-org/docs, ops@example.com" {
+		t.Errorf("unexpected code owners field %+v", last)
+	}
+}
+
+// fakeGitHub implementa application.GitHubClient con respuestas fijas; err hace fallar toda consulta.
+type fakeGitHub struct {
+	jobs []application.WorkflowJob
+	logs string
+	err  error
+}
+
+func (f *fakeGitHub) PullRequestStats(context.Context, string, int) (*application.PullRequestStats, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &application.PullRequestStats{Commits: 1, Additions: 7, Deletions: 2, ChangedFiles: 1}, nil
+}
+
+func (f *fakeGitHub) WorkflowRunJobs(context.Context, string, int64) ([]application.WorkflowJob, error) {
+	return f.jobs, f.err
+}
+
+func (f *fakeGitHub) CheckRuns(context.Context, string, string) ([]application.CheckRun, error) {
+	return nil, f.err
+}
+
+func (f *fakeGitHub) JobLogs(context.Context, string, int64) ([]byte, error) {
+	return []byte(f.logs), f.err
+}
+
+// TestGitHubEnrichment verifica que los datos de la API se añaden al embed y que un fallo
+// de la API no impide la notificación.
+func TestGitHubEnrichment(t *testing.T) {
+	github := &fakeGitHub{jobs: []application.WorkflowJob{
+		{Name: "lint", Conclusion: "success", HTMLURL: "https://example.test/lint"},
+		{Name: "test", Conclusion: "failure", HTMLURL: "https://example.test/test", Steps: []application.WorkflowStep{
+			{Number: 1, Name: "Set up job", Conclusion: "success"},
+			{Number: 2, Name: "go test ./...", Conclusion: "failure"},
+		}},
+	}}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithGitHubEnrichment(github))
+
+	for _, fixture := range []string{"workflow_run.completed.failure", "pull_request_review.submitted.approved"} {
+		event := application.Event{Name: strings.Split(fixture, ".")[0], Payload: readFixture(t, fixture)}
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+	}
+	assertField(t, notifier.sent[0].Payload, "Failed Jobs", "[test](https://example.test/test) › `go test ./...`")
+	assertField(t, notifier.sent[1].Payload, "Changes", "+7 / -2 in 1 file (1 commit)")
+
+	github.err = errors.New("API rate limit exceeded")
+	notifier.sent = nil
+	if err := service.Process(context.Background(), application.Event{Name: "workflow_run", Payload: readFixture(t, "workflow_run.completed.failure")}); err != nil {
+		t.Fatalf("enrichment failure must not fail the delivery: %v", err)
+	}
+	for _, field := range notifier.sent[0].Payload.Embeds[0].Fields {
+		if field.Name == "Failed Jobs" {
+			t.Errorf("unexpected field after API failure: %+v", field)
+		}
+	}
+}
+
+// TestJobLogExcerpt verifica que una ejecución fallida lleva el extracto del log alrededor del
+// primer error y el log completo como adjunto.
+func TestJobLogExcerpt(t *testing.T) {
+	var log strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&log, "2024-05-01T12:00:%02d.1234567Z line %d\n", i, i)
+	}
+	log.WriteString("2024-05-01T12:00:31.1234567Z --- FAIL: TestRetry (0.01s)\n")
+	log.WriteString("2024-05-01T12:00:32.1234567Z ##[error]Process completed with exit code 1.\n")
+	for i := 33; i <= 40; i++ {
+		fmt.Fprintf(&log, "2024-05-01T12:00:%02d.1234567Z cleanup %d\n", i, i)
+	}
+	github := &fakeGitHub{
+		jobs: []application.WorkflowJob{{ID: 7, Name: "test (ubuntu)", Conclusion: "failure", HTMLURL: "https://example.test/test"}},
+		logs: log.String(),
+	}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithGitHubEnrichment(github), application.WithJobLogExcerpts(5))
+
+	event := application.Event{Name: "workflow_run", Payload: readFixture(t, "workflow_run.completed.failure")}
+	if err := service.Process(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	payload := notifier.sent[0].Payload
+	assertField(t, payload, "Log Excerpt (test (ubuntu))", "```\nline 29\nline 30\n--- FAIL: TestRetry (0.01s)\n##[error]Process completed with exit code 1.\ncleanup 33\n```")
+	if len(payload.Files) != 1 || payload.Files[0].Name != "test-ubuntu.log" || string(payload.Files[0].Content) != log.String() {
+		t.Errorf("expected the full log attached, got %d files", len(payload.Files))
+	}
+}
+
+// TestJobLogSkippedNearDeadline verifica que, si a la entrega le queda poco tiempo, la
+// notificación sale con los jobs fallidos pero sin descargar ni adjuntar el log.
+func TestJobLogSkippedNearDeadline(t *testing.T) {
+	github := &fakeGitHub{
+		jobs: []application.WorkflowJob{{ID: 7, Name: "test (ubuntu)", Conclusion: "failure", HTMLURL: "https://example.test/test"}},
+		logs: "##[error]Process completed with exit code 1.\n",
+	}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithGitHubEnrichment(github), application.WithJobLogExcerpts(5))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	event := application.Event{Name: "workflow_run", Payload: readFixture(t, "workflow_run.completed.failure")}
+	if err := service.Process(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	payload := notifier.sent[0].Payload
+	if len(payload.Files) != 0 {
+		t.Errorf("expected no log attachment near the deadline, got %d files", len(payload.Files))
+	}
+	var failedJobs bool
+	for _, field := range payload.Embeds[0].Fields {
+		failedJobs = failedJobs || field.Name == "Failed Jobs"
+		if strings.HasPrefix(field.Name, "Log Excerpt") {
+			t.Errorf("unexpected log excerpt near the deadline: %+v", field)
+		}
+	}
+	if !failedJobs {
+		t.Error("expected the failed jobs field even without the log")
+	}
+}
+
+// memoryRunHistory implementa application.RunHistoryStore en memoria.
+type memoryRunHistory struct {
+	results []application.RunResult
+}
+
+func (m *memoryRunHistory) SaveRunResult(_ context.Context, result application.RunResult) error {
+	m.results = append(m.results, result)
+	return nil
+}
+
+func (m *memoryRunHistory) ListRunResults(_ context.Context, filter application.RunFilter) ([]application.RunResult, error) {
+	var results []application.RunResult
+	for _, result := range m.results {
+		if (filter.Repo == "" || result.Repo == filter.Repo) && (filter.WorkflowID == 0 || result.WorkflowID == filter.WorkflowID) && !result.CompletedAt.Before(filter.Since) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func (m *memoryRunHistory) PruneRunResults(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+// TestFlakyDetection verifica que un commit que falla y pasa en un re-run se marca como
+// flaky, que las fallas de un workflow inestable llevan su flip rate y el reporte semanal.
+func TestFlakyDetection(t *testing.T) {
+	history := &memoryRunHistory{}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithFlakyDetection(history, 14*24*time.Hour, 20))
+
+	failure := readFixture(t, "workflow_run.completed.failure")
+	var rerun map[string]any
+	if err := json.Unmarshal(failure, &rerun); err != nil {
+		t.Fatal(err)
+	}
+	rerun["workflow_run"].(map[string]any)["conclusion"] = "success"
+	rerun["workflow_run"].(map[string]any)["run_attempt"] = 2
+	success, _ := json.Marshal(rerun)
+	// Una ejecución nueva del mismo commit que pasa a la primera no es un re-run
+	rerun["workflow_run"].(map[string]any)["id"] = 30433643
+	rerun["workflow_run"].(map[string]any)["run_attempt"] = 1
+	newRun, _ := json.Marshal(rerun)
+
+	// Las vistas previas y las reproducciones no entran en el historial
+	previews := []context.Context{application.WithDryRun(context.Background()), context.Background()}
+	for i, ctx := range previews {
+		event := application.Event{Name: "workflow_run", Payload: failure}
+		if i == 1 {
+			event.ReplayOf = "original-delivery"
+		}
+		if err := service.Process(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(history.results) != 0 {
+		t.Fatalf("dry-run and replayed deliveries must not be saved, got %+v", history.results)
+	}
+	notifier.sent = nil
+
+	for _, payload := range [][]byte{failure, newRun} {
+		if err := service.Process(context.Background(), application.Event{Name: "workflow_run", Payload: payload}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, field := range notifier.sent[1].Payload.Embeds[0].Fields {
+		if field.Name == "🎲 Flaky" {
+			t.Errorf("a new run of the same commit must not be flagged: %+v", field)
+		}
+	}
+	history.results, notifier.sent = nil, nil
+
+	for _, payload := range [][]byte{failure, success} {
+		if err := service.Process(context.Background(), application.Event{Name: "workflow_run", Payload: payload}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertField(t, notifier.sent[1].Payload, "🎲 Flaky", "This run failed earlier and passed on attempt 2")
+
+	// Cuatro commits más que pasaron a la primera: 1 de 5 commits flaky (20%)
+	completed := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		history.results = append(history.results, application.RunResult{
+			Repo: "octo-org/hello-world", WorkflowID: 68405741, Workflow: "CI", HeadSHA: fmt.Sprintf("sha%d", i),
+			RunID: int64(i), Attempt: 1, Conclusion: "success", CompletedAt: completed,
+		})
+	}
+	notifier.sent = nil
+	if err := service.Process(context.Background(), application.Event{Name: "workflow_run", Payload: failure}); err != nil {
+		t.Fatal(err)
+	}
+	assertField(t, notifier.sent[0].Payload, "🎲 Flakiness", "likely flaky (20% recent flip rate)")
+
+	// El reporte cubre la última semana
+	now := time.Now()
+	history.results = []application.RunResult{
+		{Repo: "octo-org/hello-world", WorkflowID: 1, Workflow: "CI", HeadSHA: "a", Conclusion: "failure", CompletedAt: now.Add(-time.Hour)},
+		{Repo: "octo-org/hello-world", WorkflowID: 1, Workflow: "CI", HeadSHA: "a", Attempt: 2, Conclusion: "success", CompletedAt: now},
+		{Repo: "octo-org/hello-world", WorkflowID: 1, Workflow: "CI", HeadSHA: "b", Conclusion: "success", CompletedAt: now},
+		{Repo: "octo-org/hello-world", WorkflowID: 2, Workflow: "Lint", HeadSHA: "a", Conclusion: "success", CompletedAt: now},
+	}
+	notifier.sent = nil
+	if err := service.PublishReport(context.Background(), application.FlakyReport); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Destination != "testing" {
+		t.Fatalf("expected one report in testing, got %+v", notifier.sent)
+	}
+	assertField(t, notifier.sent[0].Payload, "octo-org/hello-world · CI", "50% flip rate (1 of 2 commits)")
+	if fields := notifier.sent[0].Payload.Embeds[0].Fields; len(fields) != 1 {
+		t.Errorf("workflows without flaky commits must not be listed: %+v", fields)
+	}
+	if err := service.PublishReport(context.Background(), "unknown"); !errors.Is(err, application.ErrUnknownReport) {
+		t.Errorf("expected ErrUnknownReport, got %v", err)
+	}
+}
+
+// memoryBranchStore implementa application.BranchStateStore en memoria.
+type memoryBranchStore map[string]application.BranchState
+
+func (m memoryBranchStore) GetBranchState(_ context.Context, key string) (application.BranchState, bool, error) {
+	state, ok := m[key]
+	return state, ok, nil
+}
+
+func (m memoryBranchStore) SaveBranchState(_ context.Context, key string, state application.BranchState) error {
+	m[key] = state
+	return nil
+}
+
+// mainBranchRun adapta el fixture de workflow_run.completed.failure a una ejecución en main.
+func mainBranchRun(t *testing.T, conclusion, sha string, completedAt time.Time) []byte {
+	t.Helper()
+	var event map[string]any
+	if err := json.Unmarshal(readFixture(t, "workflow_run.completed.failure"), &event); err != nil {
+		t.Fatal(err)
+	}
+	run := event["workflow_run"].(map[string]any)
+	run["head_branch"] = "main"
+	run["conclusion"] = conclusion
+	run["head_sha"] = sha
+	run["updated_at"] = completedAt.Format(time.RFC3339)
+	run["pull_requests"] = []any{}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+// TestBranchHealth verifica los avisos de rama principal rota y arreglada, y que las fallas
+// repetidas se suprimen si así se configura.
+func TestBranchHealth(t *testing.T) {
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier,
+		application.WithBranchHealth(memoryBranchStore{}, true),
+		application.WithMentions(map[string]string{"octocat": "222"}, nil))
+
+	start := time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC)
+	processWith := func(ctx context.Context, payload []byte) {
+		t.Helper()
+		if err := service.Process(ctx, application.Event{Name: "workflow_run", Payload: payload}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	process := func(payload []byte) {
+		t.Helper()
+		processWith(context.Background(), payload)
+	}
+
+	// Una vista previa de la falla no deja la rama como rota
+	processWith(application.WithDryRun(context.Background()), mainBranchRun(t, "failure", "aaaaaaa1111", start))
+	if len(notifier.sent) != 0 {
+		t.Fatalf("dry-run must not send, got %d", len(notifier.sent))
+	}
+
+	process(mainBranchRun(t, "failure", "aaaaaaa1111", start))
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected the run notification and the broken alert, got %d", len(notifier.sent))
+	}
+	broken := notifier.sent[1].Payload
+	if broken.Embeds[0].Title != "🔥 main is broken: CI" || broken.Content != "<@222> main is broken" {
+		t.Errorf("unexpected broken alert %q / %q", broken.Embeds[0].Title, broken.Content)
+	}
+	if !strings.Contains(broken.Embeds[0].Description, "[`aaaaaaa`](https://github.com/octo-org/hello-world/commit/aaaaaaa1111)") {
+		t.Errorf("broken alert must link the first failing commit: %s", broken.Embeds[0].Description)
+	}
+
+	notifier.sent = nil
+	process(mainBranchRun(t, "failure", "bbbbbbb2222", start.Add(30*time.Minute)))
+	if len(notifier.sent) != 0 {
+		t.Errorf("repeated failure must be suppressed, sent %d", len(notifier.sent))
+	}
+
+	process(mainBranchRun(t, "success", "ccccccc3333", start.Add(90*time.Minute)))
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected the run notification and the fixed alert, got %d", len(notifier.sent))
+	}
+	fixed := notifier.sent[1].Payload.Embeds[0]
+	if fixed.Title != "🩹 main fixed: CI" || !strings.Contains(fixed.Description, "broken for **1h30m0s** (2 failed runs)") {
+		t.Errorf("unexpected fixed alert %q: %s", fixed.Title, fixed.Description)
+	}
+
+	// Las ramas de feature no cambian el estado de main
+	notifier.sent = nil
+	process(readFixture(t, "workflow_run.completed.failure"))
+	if len(notifier.sent) != 1 {
+		t.Errorf("feature branch failure must only notify the run, sent %d", len(notifier.sent))
+	}
+}
+
+// memoryPullRequestStore implementa application.PullRequestStore en memoria.
+type memoryPullRequestStore map[string]application.TrackedPullRequest
+
+func (m memoryPullRequestStore) GetPullRequest(_ context.Context, key string) (application.TrackedPullRequest, bool, error) {
+	pr, ok := m[key]
+	return pr, ok, nil
+}
+
+func (m memoryPullRequestStore) SavePullRequest(_ context.Context, key string, pr application.TrackedPullRequest) error {
+	m[key] = pr
+	return nil
+}
+
+func (m memoryPullRequestStore) DeletePullRequest(_ context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func (m memoryPullRequestStore) ListPullRequests(context.Context) ([]application.TrackedPullRequest, error) {
+	var prs []application.TrackedPullRequest
+	for _, pr := range m {
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// TestReviewReminders verifica el seguimiento de pull requests abiertos y sus recordatorios.
+func TestReviewReminders(t *testing.T) {
+	store := memoryPullRequestStore{}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier,
+		application.WithReviewReminders(store, application.ReviewReminderConfig{Threshold: time.Hour, SnoozeLabel: "snooze-reminders"}),
+		application.WithMentions(map[string]string{"hubot": "111"}, nil))
+
+	var snoozed map[string]any
+	if err := json.Unmarshal(readFixture(t, "pull_request.opened"), &snoozed); err != nil {
+		t.Fatal(err)
+	}
+	snoozed["pull_request"].(map[string]any)["number"] = 43
+	snoozed["pull_request"].(map[string]any)["labels"] = []map[string]any{{"name": "Snooze-Reminders"}}
+	snoozedPayload, _ := json.Marshal(snoozed)
+	// Sin revisores ni equipos pendientes (ej: ya aprobado) no espera revisión
+	unrequested := snoozed["pull_request"].(map[string]any)
+	unrequested["number"], unrequested["labels"] = 44, nil
+	unrequested["requested_reviewers"], unrequested["requested_teams"] = nil, nil
+	unrequestedPayload, _ := json.Marshal(snoozed)
+	for _, payload := range [][]byte{readFixture(t, "pull_request.opened"), snoozedPayload, unrequestedPayload} {
+		if err := service.Process(context.Background(), application.Event{Name: "pull_request", Payload: payload}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(store) != 3 {
+		t.Fatalf("expected three tracked pull requests, got %d", len(store))
+	}
+
+	publish := func() {
+		t.Helper()
+		notifier.sent = nil
+		if err := service.PublishReport(context.Background(), application.ReviewReminderReport); err != nil {
+			t.Fatal(err)
+		}
+	}
+	publish()
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected one reminder (the others are snoozed or have no pending reviewers), got %d", len(notifier.sent))
+	}
+	reminder := notifier.sent[0].Payload
+	if reminder.Embeds[0].Title != "⏰ Waiting for Review #42: Add retry with backoff to the Discord notifier" ||
+		!strings.HasPrefix(reminder.Content, "<@111>") {
+		t.Errorf("unexpected reminder %q / %q", reminder.Embeds[0].Title, reminder.Content)
+	}
+
+	// Ya recordado: el siguiente espera otro umbral sin actividad
+	publish()
+	if len(notifier.sent) != 0 {
+		t.Errorf("reminder must not repeat before another threshold, sent %d", len(notifier.sent))
+	}
+
+	if err := service.Process(context.Background(), application.Event{Name: "pull_request", Payload: readFixture(t, "pull_request.closed.merged")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["octo-org/hello-world#42"]; ok {
+		t.Error("closed pull request must no longer be tracked")
+	}
+
+	// Una vista previa o una reproducción del "opened" no vuelve a seguir el pull request cerrado
+	for _, event := range []struct {
+		ctx      context.Context
+		replayOf string
+	}{{application.WithDryRun(context.Background()), ""}, {context.Background(), "original-delivery"}} {
+		opened := application.Event{Name: "pull_request", Payload: readFixture(t, "pull_request.opened"), ReplayOf: event.replayOf}
+		if err := service.Process(event.ctx, opened); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := store["octo-org/hello-world#42"]; ok {
+		t.Error("dry-run and replayed deliveries must not track a pull request")
+	}
+}
+
+// memoryHeldStore implementa application.HeldNotificationStore en memoria.
+type memoryHeldStore struct {
+	held   []application.HeldNotification
+	nextID uint64
+}
+
+func (m *memoryHeldStore) HoldNotification(_ context.Context, held application.HeldNotification) error {
+	m.nextID++
+	held.ID = m.nextID
+	m.held = append(m.held, held)
+	return nil
+}
+
+func (m *memoryHeldStore) ListHeldNotifications(context.Context) ([]application.HeldNotification, error) {
+	return slices.Clone(m.held), nil
+}
+
+func (m *memoryHeldStore) UpdateHeldNotification(_ context.Context, held application.HeldNotification) error {
+	if i := slices.IndexFunc(m.held, func(n application.HeldNotification) bool { return n.ID == held.ID }); i >= 0 {
+		m.held[i] = held
+	}
+	return nil
+}
+
+func (m *memoryHeldStore) DeleteHeldNotification(_ context.Context, id uint64) error {
+	m.held = slices.DeleteFunc(m.held, func(held application.HeldNotification) bool { return held.ID == id })
+	return nil
+}
+
+// TestQuietHours verifica que en horario de silencio se retienen (o descartan) las notificaciones
+// no críticas y que las retenidas se publican agrupadas al terminar el horario.
+func TestQuietHours(t *testing.T) {
+	now := time.Now().UTC()
+	sinceMidnight := now.Sub(now.Truncate(24 * time.Hour))
+	// Un horario que cubre la hora actual (puede cruzar la medianoche)
+	around := application.QuietHours{Start: (sinceMidnight + 23*time.Hour) % (24 * time.Hour), End: (sinceMidnight + time.Hour) % (24 * time.Hour)}
+	dropped := around
+	dropped.Drop = true
+
+	held := &memoryHeldStore{}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier,
+		application.WithBranchHealth(memoryBranchStore{}, false),
+		application.WithQuietHours(held, map[string]application.QuietHours{"testing": around, "pull_request/opened": dropped}))
+
+	for _, event := range []application.Event{
+		{Name: "workflow_run", Payload: readFixture(t, "workflow_run.completed.failure")},
+		{Name: "workflow_run", Payload: mainBranchRun(t, "failure", "aaaaaaa1111", now)},
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.opened")},
+	} {
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Solo el aviso de main roto (crítico) se publica; las dos ejecuciones quedan retenidas y el PR se descarta
+	if len(notifier.sent) != 1 || !strings.HasPrefix(notifier.sent[0].Payload.Embeds[0].Title, "🔥 main is broken") {
+		t.Fatalf("expected only the critical alert, got %+v", notifier.sent)
+	}
+	if len(held.held) != 2 {
+		t.Fatalf("expected two held notifications, got %d", len(held.held))
+	}
+
+	notifier.sent = nil
+	if err := service.PublishReport(context.Background(), application.ReleaseHeldReport); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("held notifications must wait for the end of quiet hours, sent %d", len(notifier.sent))
+	}
+	for i := range held.held {
+		held.held[i].ReleaseAt = now.Add(-time.Minute)
+	}
+	if err := service.PublishReport(context.Background(), application.ReleaseHeldReport); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 1 || len(notifier.sent[0].Payload.Embeds) != 2 || notifier.sent[0].Destination != "testing" {
+		t.Fatalf("expected one batched message with both runs, got %+v", notifier.sent)
+	}
+	if len(held.held) != 0 {
+		t.Errorf("released notifications must be deleted, %d left", len(held.held))
+	}
+}
+
+// TestQuietHoursDeadLetter verifica que una notificación retenida que Discord rechaza siempre se
+// aparta tras varios intentos, deja de publicarse y se borra al vencer su retención.
+func TestQuietHoursDeadLetter(t *testing.T) {
+	held := &memoryHeldStore{held: []application.HeldNotification{{
+		ID:          1,
+		Destination: "testing",
+		Payload:     application.DiscordPayload{Embeds: []application.DiscordEmbed{{Title: "held"}}},
+		ReleaseAt:   time.Now().Add(-time.Minute),
+	}}}
+	notifier := &capturingNotifier{err: errors.New("discord rejected the message")}
+	service := application.NewWebhookService(notifier, application.WithQuietHours(held, nil))
+
+	for range 12 {
+		if err := service.PublishReport(context.Background(), application.ReleaseHeldReport); err == nil {
+			t.Fatal("a failed release must be reported")
+		}
+	}
+	if len(held.held) != 1 || !held.held[0].DeadLetter || held.held[0].Attempts != 12 {
+		t.Fatalf("expected the notification to be dead-lettered after 12 attempts, got %+v", held.held)
+	}
+
+	notifier.sent = nil
+	if err := service.PublishReport(context.Background(), application.ReleaseHeldReport); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Errorf("dead-lettered notifications must not be released, sent %d", len(notifier.sent))
+	}
+
+	held.held[0].FailedAt = time.Now().Add(-8 * 24 * time.Hour)
+	if err := service.PublishReport(context.Background(), application.ReleaseHeldReport); err != nil {
+		t.Fatal(err)
+	}
+	if len(held.held) != 0 {
+		t.Errorf("expired dead letters must be deleted, %d left", len(held.held))
+	}
+}
+
+// memoryDeliveryStore implementa application.DeliveryStore en memoria (solo los filtros de tiempo).
+type memoryDeliveryStore struct {
+	records []application.DeliveryRecord
+}
+
+func (m *memoryDeliveryStore) SaveDelivery(_ context.Context, record application.DeliveryRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *memoryDeliveryStore) GetDelivery(_ context.Context, id string) (*application.DeliveryRecord, error) {
+	for i := len(m.records) - 1; i >= 0; i-- {
+		if m.records[i].ID == id {
+			return &m.records[i], nil
+		}
+	}
+	return nil, application.ErrDeliveryNotFound
+}
+
+func (m *memoryDeliveryStore) ListDeliveries(_ context.Context, filter application.DeliveryFilter) ([]application.DeliveryRecord, error) {
+	var matching []application.DeliveryRecord
+	for i := len(m.records) - 1; i >= 0; i-- {
+		record := m.records[i]
+		if record.ReceivedAt.Before(filter.Since) || (!filter.Until.IsZero() && !record.ReceivedAt.Before(filter.Until) &&
+			!(record.ReceivedAt.Equal(filter.Until) && record.ID < filter.BeforeID)) {
+			continue
+		}
+		matching = append(matching, record)
+	}
+	matching = matching[min(filter.Offset, len(matching)):]
+	return matching[:min(filter.Limit, len(matching))], nil
+}
+
+func (m *memoryDeliveryStore) PruneDeliveries(_ context.Context, before time.Time) (int, error) {
+	kept := slices.DeleteFunc(slices.Clone(m.records), func(record application.DeliveryRecord) bool {
+		return record.ReceivedAt.Before(before)
+	})
+	pruned := len(m.records) - len(kept)
+	m.records = kept
+	return pruned, nil
+}
+
+// TestDuplicateDelivery verifica que una entrega reenviada con el mismo ID no vuelve a notificar,
+// salvo que la original haya fallado o se pida reproducirla.
+func TestDuplicateDelivery(t *testing.T) {
+	deliveries := &memoryDeliveryStore{}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithDeliveryStore(deliveries, false))
+	event := application.Event{Name: "pull_request", DeliveryID: "delivery-1", Payload: readFixture(t, "pull_request.opened")}
+
+	if err := service.Process(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Process(context.Background(), event); !errors.Is(err, application.ErrDuplicateDelivery) {
+		t.Fatalf("expected ErrDuplicateDelivery, got %v", err)
+	}
+	if len(notifier.sent) != 1 || len(deliveries.records) != 1 {
+		t.Fatalf("a duplicate must not notify or be recorded again, sent %d, recorded %d", len(notifier.sent), len(deliveries.records))
+	}
+
+	replay := event
+	replay.DeliveryID, replay.ReplayOf = "replay-delivery-1", event.DeliveryID
+	if err := service.Process(context.Background(), replay); err != nil {
+		t.Fatal(err)
+	}
+
+	// Una entrega que falló se procesa de nuevo cuando GitHub la reenvía
+	notifier.err = errors.New("discord unavailable")
+	failed := application.Event{Name: "pull_request", DeliveryID: "delivery-2", Payload: readFixture(t, "pull_request.opened")}
+	if err := service.Process(context.Background(), failed); err == nil {
+		t.Fatal("expected the first attempt to fail")
+	}
+	notifier.err = nil
+	if err := service.Process(context.Background(), failed); err != nil {
+		t.Fatalf("a failed delivery must be processed again, got %v", err)
+	}
+	if len(notifier.sent) != 4 {
+		t.Errorf("expected the replay and both attempts of the failed delivery to notify, sent %d", len(notifier.sent))
+	}
+}
+
+// TestDeliveryRetention verifica que la tarea de retención borra solo las entregas vencidas.
+func TestDeliveryRetention(t *testing.T) {
+	now := time.Now().UTC()
+	deliveries := &memoryDeliveryStore{records: []application.DeliveryRecord{
+		{ID: "old", ReceivedAt: now.Add(-91 * 24 * time.Hour)},
+		{ID: "recent", ReceivedAt: now.Add(-89 * 24 * time.Hour)},
+	}}
+	service := application.NewWebhookService(&capturingNotifier{},
+		application.WithDeliveryStore(deliveries, false),
+		application.WithDeliveryRetention(90*24*time.Hour))
+
+	if err := service.PublishReport(context.Background(), application.DeliveryRetentionReport); err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries.records) != 1 || deliveries.records[0].ID != "recent" {
+		t.Errorf("expected only the recent delivery to remain, got %+v", deliveries.records)
+	}
+}
+
+// TestDigest verifica las secciones del digest armado con el historial de entregas.
+func TestDigest(t *testing.T) {
+	deliveries := &memoryDeliveryStore{}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier,
+		application.WithDeliveryStore(deliveries, false),
+		application.WithDigest(application.DigestConfig{Destination: "development", Period: 24 * time.Hour}))
+
+	var waiting map[string]any
+	if err := json.Unmarshal(readFixture(t, "pull_request.opened"), &waiting); err != nil {
+		t.Fatal(err)
+	}
+	waiting["pull_request"].(map[string]any)["number"] = 43
+	waitingPayload, _ := json.Marshal(waiting)
+	release := []byte(`{"action": "published", "release": {"tag_name": "v1.4.0", "name": "v1.4.0", "html_url": "https://github.com/octo-org/hello-world/releases/tag/v1.4.0"}, "repository": {"full_name": "octo-org/hello-world"}}`)
+
+	for _, event := range []application.Event{
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.opened")},
+		{Name: "pull_request", Payload: waitingPayload},
+		{Name: "workflow_run", Payload: readFixture(t, "workflow_run.completed.failure")},
+		{Name: "workflow_run", Payload: readFixture(t, "workflow_run.completed.success")},
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.closed.merged")},
+		{Name: "release", Payload: release},
+	} {
+		// Los eventos sin manejador (release) también quedan en el historial
+		if err := service.Process(context.Background(), event); err != nil && !errors.Is(err, application.ErrEventNotHandled) {
+			t.Fatal(err)
+		}
+	}
+	notifier.sent = nil
+	if err := service.PublishReport(context.Background(), application.DigestReport("development")); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Destination != "development" {
+		t.Fatalf("expected one digest in development, got %+v", notifier.sent)
+	}
+	embed := notifier.sent[0].Payload.Embeds[0]
+	if embed.Title != "📰 Daily Digest" {
+		t.Errorf("unexpected title %q", embed.Title)
+	}
+	assertField(t, notifier.sent[0].Payload, "Pull Requests", "🆕 2 opened · 🔀 1 merged\n[octo-org/hello-world#42](https://github.com/octo-org/hello-world/pull/42) Add retry with backoff to the Discord notifier")
+	assertField(t, notifier.sent[0].Payload, "Waiting Longest for Review", "[octo-org/hello-world#43](https://github.com/octo-org/hello-world/pull/42) Add retry with backoff to the Discord notifier — waiting 0m")
+	assertField(t, notifier.sent[0].Payload, "CI Pass Rate", "**CI** (octo-org/hello-world) — 50% (1/2)")
+	assertField(t, notifier.sent[0].Payload, "Top Failing Workflows", "**CI** (octo-org/hello-world) — 1 failure")
+	assertField(t, notifier.sent[0].Payload, "Releases", "[v1.4.0](https://github.com/octo-org/hello-world/releases/tag/v1.4.0) octo-org/hello-world")
+}
+
+// TestLifecycleMetrics verifica las métricas calculadas con el historial de entregas.
+func TestLifecycleMetrics(t *testing.T) {
+	deliveries := &memoryDeliveryStore{}
+	service := application.NewWebhookService(&capturingNotifier{},
+		application.WithDeliveryStore(deliveries, false),
+		application.WithDeploymentEnvironments("production"))
+
+	deployment := func(state, environment string) []byte {
+		return []byte(fmt.Sprintf(`{"action": "created", "deployment_status": {"state": %q, "environment": %q}, "deployment": {"sha": "abc123"}, "repository": {"full_name": "octo-org/hello-world"}}`, state, environment))
+	}
+	for _, event := range []application.Event{
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.opened")},
+		{Name: "pull_request_review", Payload: readFixture(t, "pull_request_review.submitted.changes_requested")},
+		{Name: "pull_request_review", Payload: readFixture(t, "pull_request_review.submitted.approved")},
+		{Name: "pull_request", Payload: readFixture(t, "pull_request.closed.merged")},
+		{Name: "deployment_status", Payload: deployment("success", "staging")},
+		{Name: "deployment_status", Payload: deployment("success", "Production")},
+		{Name: "deployment_status", Payload: deployment("failure", "production")},
+	} {
+		if err := service.Process(context.Background(), event); err != nil && !errors.Is(err, application.ErrEventNotHandled) {
+			t.Fatal(err)
+		}
+	}
+
+	// El merge del fixture es de 2024: el período empieza antes
+	metrics, err := service.LifecycleMetrics(context.Background(), application.MetricsFilter{
+		Repo:  "octo-org/hello-world",
+		Since: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Now().Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prs, deploys := metrics.PullRequests, metrics.Deployments
+	if prs.Merged != 1 || prs.TimeToMerge.Median != (55*time.Hour+7*time.Minute+15*time.Second).Seconds() {
+		t.Errorf("unexpected merge metrics %+v", prs)
+	}
+	if prs.TimeToFirstReview.Count != 1 || prs.ReviewIterations.Median != 2 {
+		t.Errorf("expected one reviewed pull request with two review rounds, got %+v / %+v", prs.TimeToFirstReview, prs.ReviewIterations)
+	}
+	if deploys.Successful != 1 || deploys.Failed != 1 || deploys.ChangeFailureRate != 0.5 || deploys.LeadTime.Count != 1 {
+		t.Errorf("unexpected deployment metrics %+v", deploys)
+	}
+}
+
+// assertField comprueba que el primer embed del payload tiene el campo name con el valor want.
+func assertField(t *testing.T, payload application.DiscordPayload, name, want string) {
+	t.Helper()
+	for _, field := range payload.Embeds[0].Fields {
+		if field.Name == name {
+			if field.Value != want {
+				t.Errorf("field %q = %q, want %q", name, field.Value, want)
+			}
+			return
+		}
+	}
+	t.Errorf("field %q not found in %+v", name, payload.Embeds[0].Fields)
+}
+
+// readFixture lee testdata/fixtures/<name>.json.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "fixtures", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+// assertGolden compara got (serializado como JSON indentado) con el archivo golden.
+func assertGolden(t *testing.T, path string, got any) {
+	t.Helper()
+	data, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("output does not match %s (run with -update to accept)\n--- got ---\n%s\n--- want ---\n%s", path, data, want)
+	}
+}
+
+// memoryInstallationStore implementa application.InstallationStore en memoria.
+type memoryInstallationStore map[int64]application.Installation
+
+func (m memoryInstallationStore) SaveInstallation(_ context.Context, installation application.Installation) error {
+	m[installation.ID] = installation
+	return nil
+}
+
+func (m memoryInstallationStore) DeleteInstallation(_ context.Context, id int64) error {
+	delete(m, id)
+	return nil
+}
+
+func (m memoryInstallationStore) GetInstallation(_ context.Context, id int64) (*application.Installation, bool, error) {
+	installation, ok := m[id]
+	return &installation, ok, nil
+}
+
+func (m memoryInstallationStore) FindInstallation(_ context.Context, repo string) (*application.Installation, bool, error) {
+	for _, installation := range m {
+		if installation.Covers(repo) {
+			return &installation, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// TestInstallationTracking verifica que los eventos de la GitHub App mantienen la lista de
+// repositorios de cada instalación sin publicar nada en Discord.
+func TestInstallationTracking(t *testing.T) {
+	store := memoryInstallationStore{}
+	notifier := &capturingNotifier{}
+	service := application.NewWebhookService(notifier, application.WithInstallationStore(store))
+
+	for _, fixture := range []string{"installation.created", "installation_repositories.removed"} {
+		event := application.Event{Name: strings.Split(fixture, ".")[0], Payload: readFixture(t, fixture)}
+		if err := service.Process(context.Background(), event); err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+		if fixture == "installation.created" && !store[45678901].Covers("octo-org/infra") {
+			t.Errorf("expected octo-org/infra to be covered after install, got %+v", store[45678901])
+		}
+	}
+	installation, found, _ := store.FindInstallation(context.Background(), "octo-org/hello-world")
+	if !found || installation.ID != 45678901 || installation.Account != "octo-org" {
+		t.Errorf("unexpected installation for octo-org/hello-world: %+v (found %v)", installation, found)
+	}
+	if store[45678901].Covers("octo-org/infra") {
+		t.Errorf("octo-org/infra should not be covered after removal: %+v", store[45678901])
+	}
+	if len(notifier.sent) != 0 {
+		t.Errorf("installation events must not notify, sent %d", len(notifier.sent))
+	}
+
+	deleted := []byte(`{"action": "deleted", "installation": {"id": 45678901, "account": {"login": "octo-org"}}}`)
+	if err := service.Process(context.Background(), application.Event{Name: "installation", Payload: deleted}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store) != 0 {
+		t.Errorf("expected installation to be removed, store has %v", store)
+	}
+}
@@ -0,0 +1,175 @@
+// File: src/application/workflow_run_events.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// Las notificaciones de workflows van al canal de testing.
+const workflowRunChannel = "testing"
+
+func init() {
+	registerEventModule(registerWorkflowRunHandlers)
+}
+
+// registerWorkflowRunHandlers registra los manejadores de workflow_run.
+// Cada ejecución tiene un único mensaje: se publica al encolarse o iniciar y se edita al completarse.
+func registerWorkflowRunHandlers(s *webhookService) {
+	s.registry.Register("workflow_run", "requested", decodeEvent(s.handleWorkflowRunProgress))
+	s.registry.Register("workflow_run", "in_progress", decodeEvent(s.handleWorkflowRunProgress))
+	s.registry.Register("workflow_run", "completed", decodeEvent(s.handleWorkflowRunCompleted))
+}
+
+// handleWorkflowRunProgress publica (o actualiza) el mensaje de una ejecución encolada o en curso.
+func (s *webhookService) handleWorkflowRunProgress(ctx context.Context, _ Event, event *domain.WorkflowRunEventPayload) error {
+	run := event.WorkflowRun
+	workflow := event.Workflow
+
+	color, statusEmoji, statusText := 9807270, "🕒", "queued" // Gris
+	if event.Action == "in_progress" {
+		color, statusEmoji, statusText = 3447003, "🔄", "in progress" // Azul
+	}
+
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("%s Workflow Run %s: %s", statusEmoji, statusText, workflow.Name),
+		Description: fmt.Sprintf("Workflow **%s** is %s", workflow.Name, statusText) + pullRequestLine(event),
+		URL:         run.HTMLURL,
+		Color:       color,
+		Fields:      workflowRunFields(event),
+		Footer:      &DiscordFooter{Text: fmt.Sprintf("Workflow: %s", workflow.Path)},
+		Timestamp:   run.UpdatedAt.Format(time.RFC3339),
+	}
+	renderSpan.End()
+
+	payload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}}
+	return s.publishStatus(ctx, workflowRunChannel, workflowRunKey(event), event.Repository.FullName, workflowRunPullRequest(run), payload, false)
+}
+
+// handleWorkflowRunCompleted notifica el resultado de un workflow completado,
+// editando el mensaje publicado al iniciar la ejecución si existe.
+func (s *webhookService) handleWorkflowRunCompleted(ctx context.Context, _ Event, event *domain.WorkflowRunEventPayload) error {
+	run := event.WorkflowRun
+	workflow := event.Workflow
+
+	var color int
+	var statusEmoji string
+
+	switch run.Conclusion {
+	case "success":
+		color = 3066993
+		statusEmoji = "✅"
+	case "failure":
+		color = 15158332
+		statusEmoji = "❌"
+	case "cancelled":
+		color = 9807270
+		statusEmoji = "⏹️"
+	case "skipped":
+		color = 16776960
+		statusEmoji = "⏭️"
+	default:
+		// Un mensaje "en curso" no debe quedar así para siempre: se cierra con un estilo neutro
+		if !s.hasStatusMessage(ctx, workflowRunChannel, workflowRunKey(event)) {
+			Logger(ctx).Info("Unhandled workflow conclusion, no notification sent", "conclusion", run.Conclusion, "run_id", run.ID)
+			return nil
+		}
+		color = 9807270
+		statusEmoji = "❔"
+	}
+
+	transition, repeated := s.trackBranchHealth(ctx, event)
+	suppressed := repeated && s.suppressRepeatedFailures
+	if suppressed && !s.hasStatusMessage(ctx, workflowRunChannel, workflowRunKey(event)) {
+		// La rama ya estaba rota y se avisó: solo se actualiza el estado
+		Logger(ctx).Info("Repeated failure on broken branch, notification suppressed", "run_id", run.ID, "branch", run.HeadBranch)
+		s.flakyFields(ctx, event) // El resultado cuenta para el flip rate aunque no se notifique
+		return s.applyBranchTransition(ctx, transition)
+	}
+
+	fields := workflowRunFields(event)
+	if run.RunStartedAt != nil {
+		duration := run.UpdatedAt.Sub(*run.RunStartedAt).Round(time.Second)
+		fields = append(fields, DiscordField{Name: "Duration", Value: duration.String(), Inline: true})
+	}
+	fields = append(fields, s.flakyFields(ctx, event)...)
+	var files []DiscordFile
+	if run.Conclusion == "failure" {
+		failureFields, logs := s.failureDetails(ctx, event.Repository.FullName, run.ID)
+		fields = append(fields, failureFields...)
+		files = logs
+	}
+
+	_, renderSpan := startSpan(ctx, "notification.render")
+	messageEmbed := DiscordEmbed{
+		Title:       fmt.Sprintf("%s Workflow Run %s: %s", statusEmoji, run.Conclusion, workflow.Name),
+		Description: fmt.Sprintf("Workflow **%s** completed with status: **%s**", workflow.Name, run.Conclusion) + pullRequestLine(event),
+		URL:         run.HTMLURL, // Enlace a la ejecución específica
+		Color:       color,
+		Fields:      fields,
+		Footer:      &DiscordFooter{Text: fmt.Sprintf("Workflow: %s", workflow.Path)},
+		Timestamp:   run.UpdatedAt.Format(time.RFC3339), // Usa tiempo de completado
+	}
+	payload := DiscordPayload{Embeds: []DiscordEmbed{messageEmbed}, Files: files}
+	if run.Conclusion == "failure" && !suppressed {
+		// Avisa a quien disparó el intento (en un PR, quien hizo push); en re-runs, quien lo relanzó
+		var pings mentionList
+		s.mentionUser(&pings, workflowRunActor(run).Login)
+		pings.apply(&payload, "your workflow run failed")
+	}
+	renderSpan.End()
+
+	if err := s.publishStatus(ctx, workflowRunChannel, workflowRunKey(event), event.Repository.FullName, workflowRunPullRequest(run), payload, true); err != nil {
+		return err
+	}
+	return s.applyBranchTransition(ctx, transition)
+}
+
+// workflowRunFields construye los campos comunes de todos los estados de una ejecución.
+func workflowRunFields(event *domain.WorkflowRunEventPayload) []DiscordField {
+	run := event.WorkflowRun
+	repo := event.Repository
+	return []DiscordField{
+		{Name: "Repository", Value: fmt.Sprintf("[%s](%s)", repo.FullName, repo.HTMLURL), Inline: true},
+		{Name: "Branch", Value: fmt.Sprintf("`%s`", run.HeadBranch), Inline: true},
+		{Name: "Triggered By", Value: fmt.Sprintf("[%s](%s)", event.Sender.Login, event.Sender.HTMLURL), Inline: true},
+		{Name: "Event", Value: run.Event, Inline: true},
+		{Name: "Run ID", Value: fmt.Sprintf("[%d](%s)", run.ID, run.HTMLURL), Inline: true},
+	}
+}
+
+// pullRequestLine menciona el PR asociado a la ejecución, si está disponible.
+func pullRequestLine(event *domain.WorkflowRunEventPayload) string {
+	number := workflowRunPullRequest(event.WorkflowRun)
+	if number == 0 {
+		return ""
+	}
+	prURL := fmt.Sprintf("%s/pull/%d", event.Repository.HTMLURL, number)
+	return fmt.Sprintf("\nAssociated Pull Request: [#%d](%s)", number, prURL)
+}
+
+// workflowRunPullRequest retorna el número del primer PR asociado a la ejecución, o 0.
+func workflowRunPullRequest(run domain.WorkflowRun) int {
+	if len(run.PullRequests) == 0 {
+		return 0
+	}
+	return run.PullRequests[0].Number
+}
+
+// workflowRunActor retorna quién disparó el intento actual, o quién disparó la ejecución original.
+func workflowRunActor(run domain.WorkflowRun) domain.User {
+	if run.TriggeringActor.Login != "" {
+		return run.TriggeringActor
+	}
+	return run.Actor
+}
+
+// workflowRunKey identifica el mensaje de estado de un intento de ejecución.
+// Un re-run es un intento nuevo y tiene su propio mensaje.
+func workflowRunKey(event *domain.WorkflowRunEventPayload) string {
+	return fmt.Sprintf("%s/runs/%d/%d", event.Repository.FullName, event.WorkflowRun.ID, max(event.WorkflowRun.RunAttempt, 1))
+}
@@ -0,0 +1,111 @@
+// File: src/domain/value_objects/codeowners.go
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// CodeOwners es un archivo CODEOWNERS ya interpretado.
+// Como en GitHub, la última regla que coincide con una ruta es la que decide sus dueños.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+type codeOwnersRule struct {
+	pattern string
+	match   *regexp.Regexp
+	owners  []string // "@usuario", "@org/equipo" o un email; vacío = sin dueños
+}
+
+// ParseCodeOwners lee un archivo CODEOWNERS. Ignora comentarios y líneas vacías.
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	codeOwners := &CodeOwners{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		match, err := compileCodeOwnersPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("CODEOWNERS line %d: %w", lineNumber, err)
+		}
+		codeOwners.rules = append(codeOwners.rules, codeOwnersRule{pattern: fields[0], match: match, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	return codeOwners, nil
+}
+
+// Owners retorna los dueños de path (relativo a la raíz del repositorio, sin "/" inicial).
+func (c *CodeOwners) Owners(path string) []string {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].match.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// stripComment quita el comentario de una línea; "\#" es un "#" literal.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// compileCodeOwnersPattern traduce un patrón estilo gitignore a una expresión regular:
+//   - "/" inicial (o una "/" en medio) ancla el patrón a la raíz; sin ella coincide a cualquier profundidad
+//   - "/" final solo coincide con directorios; un directorio cubre todo su contenido
+//   - "*" no cruza directorios, "**" sí; "dir/*" cubre solo los archivos directos de dir
+func compileCodeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.ReplaceAll(pattern, `\#`, "#")
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*")
+	case !strings.HasSuffix(pattern, "/*"):
+		expr.WriteString("(?:/.*)?") // Si el patrón nombra un directorio, cubre su contenido
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
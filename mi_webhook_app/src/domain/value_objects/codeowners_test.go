@@ -0,0 +1,64 @@
+// File: src/domain/value_objects/codeowners_test.go
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sampleCodeOwners sigue los ejemplos de la documentación de CODEOWNERS de GitHub.
+const sampleCodeOwners = `
+# Dueños por defecto de todo el repositorio
+*       @octo-org/maintainers
+
+*.js    @js-owner #Comentario al final de la línea
+*.go    docs@example.com
+/build/logs/ @doctocat
+docs/*  @octo-org/docs
+apps/   @octocat
+/scripts/ @doctocat @octocat
+**/logs @octo-org/ops
+/apps/github
+\#notes.md @hubot
+`
+
+func TestCodeOwners(t *testing.T) {
+	codeOwners, err := ParseCodeOwners(strings.NewReader(sampleCodeOwners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@octo-org/maintainers"}},
+		{"web/app.js", []string{"@js-owner"}},
+		{"cmd/main.go", []string{"docs@example.com"}},
+		{"build/logs/today.txt", []string{"@octo-org/ops"}}, // La última regla que coincide gana
+		{"docs/getting-started.md", []string{"@octo-org/docs"}},
+		{"docs/build-app/troubleshooting.md", []string{"@octo-org/maintainers"}}, // docs/* no es recursivo
+		{"apps/api/server.go", []string{"@octocat"}},
+		{"services/apps/worker.rb", []string{"@octocat"}}, // apps/ coincide a cualquier profundidad
+		{"scripts/deploy.sh", []string{"@doctocat", "@octocat"}},
+		{"tools/scripts/deploy.sh", []string{"@octo-org/maintainers"}}, // /scripts/ está anclado
+		{"deeply/nested/logs/app.log", []string{"@octo-org/ops"}},
+		{"apps/github/index.ts", nil}, // Regla sin dueños
+		{"#notes.md", []string{"@hubot"}},
+	} {
+		if got := codeOwners.Owners(tc.path); !slices.Equal(got, tc.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestCodeOwnersEmpty(t *testing.T) {
+	codeOwners, err := ParseCodeOwners(strings.NewReader("# sin reglas\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := codeOwners.Owners("main.go"); got != nil {
+		t.Errorf("expected no owners, got %v", got)
+	}
+}
@@ -11,20 +11,32 @@ type PullRequestEventPayload struct {
 	PullRequest PullRequest `json:"pull_request"`
 	Repository  Repository  `json:"repository"`
 	Sender      User        `json:"sender"`
+	// Solo en "review_requested": el usuario o equipo al que se pidió revisión
+	RequestedReviewer *User `json:"requested_reviewer"`
+	RequestedTeam     *Team `json:"requested_team"`
 }
 
 type PullRequest struct {
-	ID          int         `json:"id"`
-	HTMLURL     string      `json:"html_url"` // URL para el navegador
-	Title       string      `json:"title"`
-	User        User        `json:"user"` // Quién creó el PR
-	State       string      `json:"state"`
-	Merged      bool        `json:"merged"`
-	MergedAt    *time.Time  `json:"merged_at"` // Puntero si puede ser null
-	CreatedAt   *time.Time  `json:"created_at"` // Puntero si puede ser null
-	UpdatedAt   *time.Time  `json:"updated_at"` // Puntero si puede ser null
-	Head        Branch      `json:"head"`
-	Base        Branch      `json:"base"`
+	ID                 int        `json:"id"`
+	Number             int        `json:"number"`
+	HTMLURL            string     `json:"html_url"` // URL para el navegador
+	Title              string     `json:"title"`
+	User               User       `json:"user"` // Quién creó el PR
+	State              string     `json:"state"`
+	Draft              bool       `json:"draft"`
+	Merged             bool       `json:"merged"`
+	MergedAt           *time.Time `json:"merged_at"`  // Puntero si puede ser null
+	CreatedAt          *time.Time `json:"created_at"` // Puntero si puede ser null
+	UpdatedAt          *time.Time `json:"updated_at"` // Puntero si puede ser null
+	Head               Branch     `json:"head"`
+	Base               Branch     `json:"base"`
+	RequestedReviewers []User     `json:"requested_reviewers"`
+	RequestedTeams     []Team     `json:"requested_teams"`
+	Labels             []Label    `json:"labels"`
+	Commits            int        `json:"commits"`       // Solo en eventos pull_request
+	Additions          int        `json:"additions"`     // Solo en eventos pull_request
+	Deletions          int        `json:"deletions"`     // Solo en eventos pull_request
+	ChangedFiles       int        `json:"changed_files"` // Solo en eventos pull_request
 }
 
 type Branch struct {
@@ -34,10 +46,11 @@ type Branch struct {
 }
 
 type Repository struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	FullName string `json:"full_name"` // owner/repo
-	HTMLURL  string `json:"html_url"`  // URL al repo
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"` // owner/repo
+	HTMLURL       string `json:"html_url"`  // URL al repo
+	DefaultBranch string `json:"default_branch"`
 }
 
 type User struct {
@@ -47,6 +60,116 @@ type User struct {
 	Type    string `json:"type"`
 }
 
+type Team struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"` // Identificador del equipo dentro de la organización
+	HTMLURL string `json:"html_url"`
+}
+
+type Label struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// --- Pull Request Review Event ---
+
+type PullRequestReviewEventPayload struct {
+	Action      string      `json:"action"` // "submitted", "edited" o "dismissed"
+	Review      Review      `json:"review"`
+	PullRequest PullRequest `json:"pull_request"`
+	Repository  Repository  `json:"repository"`
+	Sender      User        `json:"sender"`
+}
+
+type Review struct {
+	ID          int64      `json:"id"`
+	User        User       `json:"user"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"` // approved, changes_requested o commented
+	HTMLURL     string     `json:"html_url"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+}
+
+// --- Installation Events (GitHub App) ---
+
+type InstallationEventPayload struct {
+	Action       string                   `json:"action"` // created, deleted, suspend, unsuspend, new_permissions_accepted
+	Installation Installation             `json:"installation"`
+	Repositories []InstallationRepository `json:"repositories"` // Solo en created y deleted
+	Sender       User                     `json:"sender"`
+}
+
+type InstallationRepositoriesEventPayload struct {
+	Action              string                   `json:"action"` // added o removed
+	Installation        Installation             `json:"installation"`
+	RepositorySelection string                   `json:"repository_selection"`
+	RepositoriesAdded   []InstallationRepository `json:"repositories_added"`
+	RepositoriesRemoved []InstallationRepository `json:"repositories_removed"`
+	Sender              User                     `json:"sender"`
+}
+
+type Installation struct {
+	ID                  int64  `json:"id"`
+	Account             User   `json:"account"`
+	RepositorySelection string `json:"repository_selection"` // "all" o "selected"
+}
+
+type InstallationRepository struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Private  bool   `json:"private"`
+}
+
+// --- Release Event ---
+
+type ReleaseEventPayload struct {
+	Action     string     `json:"action"` // published, created, released, ...
+	Release    Release    `json:"release"`
+	Repository Repository `json:"repository"`
+	Sender     User       `json:"sender"`
+}
+
+type Release struct {
+	ID          int64      `json:"id"`
+	TagName     string     `json:"tag_name"`
+	Name        string     `json:"name"`
+	HTMLURL     string     `json:"html_url"`
+	Author      User       `json:"author"`
+	Draft       bool       `json:"draft"`
+	Prerelease  bool       `json:"prerelease"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// --- Deployment Status Event ---
+
+type DeploymentStatusEventPayload struct {
+	Action           string           `json:"action"` // created
+	DeploymentStatus DeploymentStatus `json:"deployment_status"`
+	Deployment       Deployment       `json:"deployment"`
+	Repository       Repository       `json:"repository"`
+	Sender           User             `json:"sender"`
+}
+
+type DeploymentStatus struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"state"` // pending, queued, in_progress, success, failure, error o inactive
+	Environment string    `json:"environment"`
+	TargetURL   string    `json:"target_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Deployment struct {
+	ID          int64     `json:"id"`
+	Sha         string    `json:"sha"`
+	Ref         string    `json:"ref"`
+	Environment string    `json:"environment"`
+	Creator     User      `json:"creator"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // --- Workflow Run Event ---
 
 type WorkflowRunEventPayload struct {
@@ -58,19 +181,37 @@ type WorkflowRunEventPayload struct {
 }
 
 type WorkflowRun struct {
-	ID           int64               `json:"id"`
-	Name         string              `json:"name"`
-	HeadBranch   string              `json:"head_branch"`
-	HeadSha      string              `json:"head_sha"`
-	RunNumber    int                 `json:"run_number"`
-	Event        string              `json:"event"`
-	Status       string              `json:"status"`
-	Conclusion   string              `json:"conclusion"` // Puede ser null si no está completed
-	WorkflowID   int64               `json:"workflow_id"`
-	HTMLURL      string              `json:"html_url"` // URL a la ejecución específica
-	CreatedAt    time.Time           `json:"created_at"` // GitHub usualmente lo envía no-null
-	UpdatedAt    time.Time           `json:"updated_at"` // GitHub usualmente lo envía no-null
-	PullRequests []WorkflowPullRequest `json:"pull_requests"`
+	ID              int64                 `json:"id"`
+	Name            string                `json:"name"`
+	HeadBranch      string                `json:"head_branch"`
+	HeadSha         string                `json:"head_sha"`
+	RunNumber       int                   `json:"run_number"`
+	RunAttempt      int                   `json:"run_attempt"`
+	Event           string                `json:"event"`
+	Status          string                `json:"status"`
+	Conclusion      string                `json:"conclusion"` // Puede ser null si no está completed
+	WorkflowID      int64                 `json:"workflow_id"`
+	HTMLURL         string                `json:"html_url"`         // URL a la ejecución específica
+	CreatedAt       time.Time             `json:"created_at"`       // GitHub usualmente lo envía no-null
+	UpdatedAt       time.Time             `json:"updated_at"`       // GitHub usualmente lo envía no-null
+	RunStartedAt    *time.Time            `json:"run_started_at"`   // Inicio del intento actual
+	Actor           User                  `json:"actor"`            // Quién disparó la primera ejecución
+	TriggeringActor User                  `json:"triggering_actor"` // Quién disparó este intento (re-runs)
+	PullRequests    []WorkflowPullRequest `json:"pull_requests"`
+	HeadCommit      *Commit               `json:"head_commit"`
+}
+
+type Commit struct {
+	ID        string       `json:"id"`
+	Message   string       `json:"message"`
+	Timestamp time.Time    `json:"timestamp"`
+	Author    CommitAuthor `json:"author"`
+}
+
+// CommitAuthor es el autor según git: no siempre corresponde a un usuario de GitHub.
+type CommitAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
 }
 
 type Workflow struct {
@@ -3,18 +3,147 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv" // Mantiene dependencia de godotenv aquí
+	"github.com/robfig/cron/v3"
 )
 
+// defaultDiscordTimeout limita cada envío a Discord cuando no se configura otro valor.
+const defaultDiscordTimeout = 10 * time.Second
+
+// defaultShutdownTimeout es el tiempo máximo que se espera a las peticiones en curso al apagar.
+const defaultShutdownTimeout = 30 * time.Second
+
+// defaultShutdownDrainDelay es cuánto se anuncia "no listo" antes de dejar de aceptar conexiones,
+// para que el balanceador lo note y deje de enviar tráfico.
+const defaultShutdownDrainDelay = 5 * time.Second
+
+// defaultDeliveryRetention es cuánto se conserva cada entrega en el historial. Cubre la ventana de
+// las métricas del ciclo de vida más el mes previo en que se buscan sus revisiones.
+const defaultDeliveryRetention = 90 * 24 * time.Hour
+
+// defaultCodeOwnersCacheTTL es cuánto tiempo se reutiliza el CODEOWNERS descargado de cada repositorio.
+const defaultCodeOwnersCacheTTL = 10 * time.Minute
+
+// defaultWorkflowLogLines es el largo del extracto de log que acompaña a una ejecución fallida.
+const defaultWorkflowLogLines = 20
+
+// defaultFlakyWindow es la ventana del flip rate: dos semanas de ejecuciones.
+const defaultFlakyWindow = 14 * 24 * time.Hour
+
+// defaultFlakyThreshold es el flip rate (%) desde el que una falla se considera probablemente flaky.
+const defaultFlakyThreshold = 20
+
+// defaultReviewReminderThreshold es el tiempo laboral sin actividad de revisión antes de
+// recordar un pull request: una jornada.
+const defaultReviewReminderThreshold = 8 * time.Hour
+
+// defaultMetricsWindow es el período de las métricas del ciclo de vida que se exportan a Prometheus.
+const defaultMetricsWindow = 30 * 24 * time.Hour
+
+// defaultWorkingHours es el calendario laboral con el que se cuenta la espera de revisión.
+const defaultWorkingHours = "Mon-Fri 09:00-17:00 UTC"
+
+// defaultReportSchedules son los horarios de los reportes periódicos: el de workflows
+// inestables, los lunes a las 9:00 (hora del servidor); los recordatorios de revisión,
+// cada hora (el calendario laboral decide cuándo un pull request está esperando), y la
+// publicación de las notificaciones retenidas en horario de silencio, cada 5 minutos; y la
+// limpieza del historial de entregas, todos los días a las 3:30.
+var defaultReportSchedules = map[string]string{
+	"flaky":              "0 9 * * 1",
+	"stale_prs":          "0 * * * *",
+	"quiet_hours":        "*/5 * * * *",
+	"delivery_retention": "30 3 * * *",
+}
+
+// Channels son los canales lógicos de Discord que la configuración conoce.
+var Channels = []string{"development", "testing"}
+
 // AppConfig mantiene la configuración de la aplicación.
 type AppConfig struct {
 	Port                         string
 	DiscordWebhookURLDevelopment string
 	DiscordWebhookURLTesting     string
-	// GithubWebhookSecret string // Descomenta si usas verificación de firma
+	DiscordTimeoutDevelopment    time.Duration     // Tiempo máximo por envío al canal development
+	DiscordTimeoutTesting        time.Duration     // Tiempo máximo por envío al canal testing
+	ShutdownTimeout              time.Duration     // Tiempo máximo para drenar peticiones al apagar
+	ShutdownDrainDelay           time.Duration     // Espera entre anunciarse "no listo" y cerrar los listeners; 0 = sin espera
+	LogLevel                     string            // debug, info, warn o error
+	TracesExporter               string            // otlp, stdout o none
+	DatabasePath                 string            // Archivo bbolt con el historial de entregas
+	StoreRawPayloads             bool              // Guarda el payload crudo de cada entrega
+	DeliveryRetention            time.Duration     // Antigüedad desde la que se borran las entregas del historial
+	AdminToken                   string            // Token Bearer de /admin; vacío deshabilita la API
+	DryRun                       bool              // Renderiza y registra notificaciones sin publicarlas
+	DryRunDestinations           []string          // Canales en dry-run aunque DryRun sea false
+	PullRequestThreads           []string          // Canales donde cada pull request tiene su propio hilo
+	DiscordUserIDs               map[string]string // Login de GitHub → ID de usuario de Discord
+	DiscordRoleIDs               map[string]string // Equipo de GitHub ("org/slug" o "slug") → ID de rol de Discord
+	GithubAPIURL                 string            // API REST de GitHub (o de GitHub Enterprise Server)
+	GithubToken                  string            // Token para la API; vacío = peticiones anónimas
+	GithubAppID                  int64             // GitHub App con la que se llama a la API; 0 = no se usa
+	GithubAppPrivateKey          string            // Clave privada PEM de la GitHub App
+	GithubEnrichment             bool              // Completa las notificaciones con datos de la API
+	WorkflowLogLines             int               // Líneas del extracto de log en ejecuciones fallidas; 0 = sin extracto
+	FlakyDetection               bool              // Guarda el resultado de cada ejecución para detectar workflows inestables (opt-in)
+	FlakyWindow                  time.Duration     // Ventana del flip rate de cada workflow
+	FlakyThreshold               int               // Flip rate (%) desde el que una falla se marca como probablemente flaky
+	ReportSchedules              map[string]string // Reporte → expresión cron; "none" lo deshabilita
+	Digests                      []DigestConfig    // Digests periódicos por canal
+	DeployEnvironments           []string          // Entornos que cuentan en las métricas de deployments; vacío = todos
+	MetricsWindow                time.Duration     // Período de las métricas del ciclo de vida en Prometheus
+	BranchAlerts                 bool              // Avisa cuando la rama principal se rompe y cuando se arregla (opt-in)
+	SuppressRepeatedFailures     bool              // No notifica las fallas de una rama principal que ya estaba rota
+	ReviewReminders              ReviewConfig      // Recordatorios de revisiones pendientes
+	QuietHours                   []QuietHours      // Horarios de silencio por destino o ruta
+	CoalesceWindow               time.Duration     // Ventana en que se agrupan los envíos de un PR o commit; 0 = sin agrupar (con agrupación, como máximo una vez)
+	CodeOwnersFile               string            // CODEOWNERS local; vacío = se descarga de cada repositorio
+	CodeOwnersCacheTTL           time.Duration     // Vigencia del CODEOWNERS descargado
+	GithubWebhookSecret          string            // Secreto con el que GitHub firma las entregas (X-Hub-Signature-256); vacío = sin verificar
+}
+
+// DigestConfig es el digest de un canal (DIGEST_<CANAL>_SCHEDULE, _PERIOD y _REPOS).
+type DigestConfig struct {
+	Channel  string
+	Schedule string        // Expresión cron
+	Period   time.Duration // Período que resume cada digest
+	Repos    []string      // Repositorios incluidos; vacío = todos
+	Metrics  bool          // Añade las métricas del ciclo de vida
+}
+
+// ReviewConfig configura los recordatorios de revisiones pendientes (REVIEW_REMINDER_*).
+type ReviewConfig struct {
+	Enabled          bool                     // Sigue los pull requests abiertos y recuerda las revisiones pendientes (opt-in)
+	Threshold        time.Duration            // Tiempo laboral sin actividad de revisión antes de recordar
+	RepoThresholds   map[string]time.Duration // Umbral por repositorio
+	SnoozeLabel      string                   // Etiqueta que pospone los recordatorios de un pull request
+	WorkingHours     WorkingHours             // Calendario con el que se cuenta la espera (WORKING_HOURS)
+	RepoWorkingHours map[string]WorkingHours  // Calendario por repositorio (WORKING_HOURS_REPOS)
+}
+
+// WorkingHours es un calendario laboral (WORKING_HOURS), ej: "Mon-Fri 09:00-17:00 America/Mexico_City".
+// Con "always" no hay calendario y cuenta todo el tiempo (Days queda vacío).
+type WorkingHours struct {
+	Days       [7]bool // Indexado por time.Weekday
+	Start, End time.Duration
+	Location   *time.Location
+}
+
+// QuietHours es el horario de silencio diario de un destino o de una ruta (QUIET_HOURS),
+// ej: "testing=22:00-07:00 America/Mexico_City drop".
+type QuietHours struct {
+	Key        string // Destino (ej: "testing") o ruta (ej: "workflow_run/completed")
+	Start, End time.Duration
+	Location   *time.Location
+	Drop       bool // Descarta las notificaciones en lugar de retenerlas hasta el final del horario
 }
 
 // LoadConfig carga la configuración desde variables de entorno.
@@ -23,7 +152,7 @@ func LoadConfig() (*AppConfig, error) {
 	err := godotenv.Load()
 	if err != nil {
 		// No es un error fatal si .env no existe (podrían estar seteadas en el sistema)
-		log.Println("WARNING: Could not load .env file, reading environment variables directly.")
+		slog.Warn("Could not load .env file, reading environment variables directly")
 	}
 
 	devURL := os.Getenv("DISCORD_WEBHOOK_URL_DEVELOPMENT")
@@ -39,18 +168,562 @@ func LoadConfig() (*AppConfig, error) {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080" // Puerto por defecto
-		log.Printf("INFO: PORT environment variable not set, using default %s", port)
+		slog.Info("PORT environment variable not set, using default", "port", port)
+	}
+
+	// DISCORD_TIMEOUT aplica a todos los canales; DISCORD_TIMEOUT_<CANAL> lo sobreescribe.
+	defaultTimeout, err := durationEnv("DISCORD_TIMEOUT", defaultDiscordTimeout)
+	if err != nil {
+		return nil, err
+	}
+	devTimeout, err := durationEnv("DISCORD_TIMEOUT_DEVELOPMENT", defaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	testTimeout, err := durationEnv("DISCORD_TIMEOUT_TESTING", defaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "info"
+	}
+
+	// Nombre estándar de OpenTelemetry; el endpoint OTLP se lee de OTEL_EXPORTER_OTLP_ENDPOINT.
+	tracesExporter := os.Getenv("OTEL_TRACES_EXPORTER")
+	if tracesExporter == "" {
+		tracesExporter = "none"
+	}
+
+	dbPath := os.Getenv("DATABASE_PATH")
+	if dbPath == "" {
+		dbPath = "data/webhooks.db"
+	}
+	// Los payloads crudos ocupan la mayor parte de la base: solo se guardan si se piden
+	storePayloads, err := boolEnv("STORE_RAW_PAYLOADS", false)
+	if err != nil {
+		return nil, err
+	}
+	deliveryRetention, err := durationEnv("DELIVERY_RETENTION", defaultDeliveryRetention)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun, err := boolEnv("DRY_RUN", false)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if err != nil {
+		return nil, err
+	}
+	// SHUTDOWN_DRAIN_DELAY=0 apaga sin esperar (ej: en desarrollo, sin balanceador)
+	drainDelay := defaultShutdownDrainDelay
+	if raw := os.Getenv("SHUTDOWN_DRAIN_DELAY"); raw != "" {
+		drainDelay, err = time.ParseDuration(raw)
+		if err != nil || drainDelay < 0 {
+			return nil, fmt.Errorf("SHUTDOWN_DRAIN_DELAY must be a non-negative duration (e.g. \"5s\"), got %q", raw)
+		}
+	}
+
+	// Menciones: "octocat=123456789012345678,hubot=..." y "octo-org/backend=876543210987654321,..."
+	userIDs, err := mapEnv("DISCORD_USER_MAP")
+	if err != nil {
+		return nil, err
+	}
+	roleIDs, err := mapEnv("DISCORD_ROLE_MAP")
+	if err != nil {
+		return nil, err
+	}
+
+	githubAPIURL := os.Getenv("GITHUB_API_URL")
+	if githubAPIURL == "" {
+		githubAPIURL = "https://api.github.com"
+	}
+	codeOwnersTTL, err := durationEnv("CODEOWNERS_CACHE_TTL", defaultCodeOwnersCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	githubEnrichment, err := boolEnv("GITHUB_ENRICHMENT", true)
+	if err != nil {
+		return nil, err
+	}
+	workflowLogLines, err := intEnv("WORKFLOW_LOG_LINES", defaultWorkflowLogLines)
+	if err != nil {
+		return nil, err
+	}
+	// Las funciones que publican avisos nuevos son opt-in: actualizar no debe empezar a
+	// escribir en canales que nadie configuró para eso
+	flakyDetection, err := boolEnv("FLAKY_DETECTION", false)
+	if err != nil {
+		return nil, err
+	}
+	flakyWindow, err := durationEnv("FLAKY_WINDOW", defaultFlakyWindow)
+	if err != nil {
+		return nil, err
+	}
+	flakyThreshold, err := intEnv("FLAKY_THRESHOLD", defaultFlakyThreshold)
+	if err != nil {
+		return nil, err
+	}
+	branchAlerts, err := boolEnv("BRANCH_ALERTS", false)
+	if err != nil {
+		return nil, err
+	}
+	suppressRepeats, err := boolEnv("SUPPRESS_REPEATED_FAILURES", false)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewReminders, err := boolEnv("REVIEW_REMINDERS", false)
+	if err != nil {
+		return nil, err
+	}
+	reviewThreshold, err := durationEnv("REVIEW_REMINDER_THRESHOLD", defaultReviewReminderThreshold)
+	if err != nil {
+		return nil, err
+	}
+	// Umbrales y calendarios por repositorio: "octo-org/api=4h" y "octo-org/api=Sun-Thu 08:00-16:00 Asia/Jerusalem"
+	reviewRepoThresholds, err := durationMapEnv("REVIEW_REMINDER_REPO_THRESHOLDS")
+	if err != nil {
+		return nil, err
+	}
+	workingHoursSpec := os.Getenv("WORKING_HOURS")
+	if workingHoursSpec == "" {
+		workingHoursSpec = defaultWorkingHours
+	}
+	workingHours, err := parseWorkingHours(workingHoursSpec)
+	if err != nil {
+		return nil, fmt.Errorf("WORKING_HOURS: %w", err)
+	}
+	repoCalendars, err := mapEnv("WORKING_HOURS_REPOS")
+	if err != nil {
+		return nil, err
+	}
+	repoWorkingHours := make(map[string]WorkingHours, len(repoCalendars))
+	for repo, spec := range repoCalendars {
+		if repoWorkingHours[repo], err = parseWorkingHours(spec); err != nil {
+			return nil, fmt.Errorf("WORKING_HOURS_REPOS entry %q: %w", repo, err)
+		}
+	}
+	snoozeLabel := os.Getenv("REVIEW_REMINDER_SNOOZE_LABEL")
+	if snoozeLabel == "" {
+		snoozeLabel = "snooze-reminders"
+	}
+	reviews := ReviewConfig{
+		Enabled:          reviewReminders,
+		Threshold:        reviewThreshold,
+		RepoThresholds:   reviewRepoThresholds,
+		SnoozeLabel:      snoozeLabel,
+		WorkingHours:     workingHours,
+		RepoWorkingHours: repoWorkingHours,
+	}
+
+	// Horarios de silencio por destino o ruta:
+	// "testing=22:00-07:00 America/Mexico_City,workflow_run/completed=00:00-08:00 UTC drop"
+	quietSpecs, err := mapEnv("QUIET_HOURS")
+	if err != nil {
+		return nil, err
+	}
+	var quietHours []QuietHours
+	for _, key := range slices.Sorted(maps.Keys(quietSpecs)) {
+		spec := quietSpecs[key]
+		quiet, err := parseQuietHours(spec)
+		if err != nil {
+			return nil, fmt.Errorf("QUIET_HOURS entry %q: %w", key, err)
+		}
+		quiet.Key = key
+		quietHours = append(quietHours, quiet)
 	}
 
-	// secret := os.Getenv("GITHUB_WEBHOOK_SECRET") // Descomenta si usas verificación
-	// if secret == "" {
-	//     log.Println("WARNING: GITHUB_WEBHOOK_SECRET environment variable not set. Signature verification disabled.")
-	// }
+	// Agrupación: los envíos de un mismo PR o commit dentro de la ventana salen en un solo mensaje (sin definir = deshabilitada).
+	// Con agrupación la entrega se registra antes de publicar, así que el envío es "como máximo una vez":
+	// un lote que Discord rechaza no se recupera con el reenvío de GitHub ni con la reproducción
+	// (ver discord_coalesced_notifications_total)
+	coalesceWindow, err := durationEnv("NOTIFICATION_COALESCE_WINDOW", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Métricas del ciclo de vida: por defecto solo cuentan los deployments a producción
+	deployEnvironments := listEnv("DEPLOY_ENVIRONMENTS")
+	if os.Getenv("DEPLOY_ENVIRONMENTS") == "" {
+		deployEnvironments = []string{"production"}
+	}
+	metricsWindow, err := durationEnv("METRICS_WINDOW", defaultMetricsWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	// Digests: solo los canales con DIGEST_<CANAL>_SCHEDULE tienen uno
+	var digests []DigestConfig
+	for _, channel := range Channels {
+		prefix := "DIGEST_" + strings.ToUpper(channel) + "_"
+		schedule := os.Getenv(prefix + "SCHEDULE")
+		if schedule == "" || schedule == "none" {
+			continue
+		}
+		period, err := durationEnv(prefix+"PERIOD", 24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		withMetrics, err := boolEnv(prefix+"METRICS", false)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, DigestConfig{Channel: channel, Schedule: schedule, Period: period, Repos: listEnv(prefix + "REPOS"), Metrics: withMetrics})
+	}
+
+	// Cada reporte se programa con <NOMBRE>_REPORT_SCHEDULE (ej: FLAKY_REPORT_SCHEDULE)
+	reportSchedules := make(map[string]string, len(defaultReportSchedules))
+	for name, spec := range defaultReportSchedules {
+		if value := os.Getenv(strings.ToUpper(name) + "_REPORT_SCHEDULE"); value != "" {
+			spec = value
+		}
+		reportSchedules[name] = spec
+	}
+
+	// GitHub App: la clave privada va en línea (GITHUB_APP_PRIVATE_KEY) o en un archivo
+	var appID int64
+	if raw := os.Getenv("GITHUB_APP_ID"); raw != "" {
+		appID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || appID <= 0 {
+			return nil, fmt.Errorf("GITHUB_APP_ID must be a positive integer, got %q", raw)
+		}
+	}
+	appPrivateKey := os.Getenv("GITHUB_APP_PRIVATE_KEY")
+	if keyFile := os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"); keyFile != "" && appPrivateKey == "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GITHUB_APP_PRIVATE_KEY_FILE: %w", err)
+		}
+		appPrivateKey = string(data)
+	}
+
+	// Sin secreto cualquiera que alcance el endpoint puede enviar entregas: las de instalaciones no se aceptan
+	secret := os.Getenv("GITHUB_WEBHOOK_SECRET")
 
 	return &AppConfig{
 		Port:                         port,
 		DiscordWebhookURLDevelopment: devURL,
 		DiscordWebhookURLTesting:     testURL,
-		// GithubWebhookSecret: secret, // Descomenta
+		DiscordTimeoutDevelopment:    devTimeout,
+		DiscordTimeoutTesting:        testTimeout,
+		ShutdownTimeout:              shutdownTimeout,
+		ShutdownDrainDelay:           drainDelay,
+		LogLevel:                     logLevel,
+		TracesExporter:               tracesExporter,
+		DatabasePath:                 dbPath,
+		StoreRawPayloads:             storePayloads,
+		DeliveryRetention:            deliveryRetention,
+		AdminToken:                   os.Getenv("ADMIN_TOKEN"),
+		DryRun:                       dryRun,
+		DryRunDestinations:           listEnv("DRY_RUN_DESTINATIONS"),
+		PullRequestThreads:           listEnv("DISCORD_PR_THREADS"),
+		DiscordUserIDs:               userIDs,
+		DiscordRoleIDs:               roleIDs,
+		GithubAPIURL:                 githubAPIURL,
+		GithubToken:                  os.Getenv("GITHUB_TOKEN"),
+		GithubAppID:                  appID,
+		GithubAppPrivateKey:          appPrivateKey,
+		GithubEnrichment:             githubEnrichment,
+		WorkflowLogLines:             workflowLogLines,
+		FlakyDetection:               flakyDetection,
+		FlakyWindow:                  flakyWindow,
+		FlakyThreshold:               flakyThreshold,
+		ReportSchedules:              reportSchedules,
+		Digests:                      digests,
+		DeployEnvironments:           deployEnvironments,
+		MetricsWindow:                metricsWindow,
+		BranchAlerts:                 branchAlerts,
+		SuppressRepeatedFailures:     suppressRepeats,
+		ReviewReminders:              reviews,
+		QuietHours:                   quietHours,
+		CoalesceWindow:               coalesceWindow,
+		CodeOwnersFile:               os.Getenv("CODEOWNERS_FILE"),
+		CodeOwnersCacheTTL:           codeOwnersTTL,
+		GithubWebhookSecret:          secret,
 	}, nil
-}
\ No newline at end of file
+}
+
+// GithubAuthenticated indica si las peticiones a la API de GitHub llevan credenciales
+// (un token o una GitHub App).
+func (c *AppConfig) GithubAuthenticated() bool {
+	return c.GithubToken != "" || c.GithubAppID != 0
+}
+
+// durationEnv lee una duración (ej: "5s", "1m") o retorna el valor por defecto si no está definida.
+func durationEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration (e.g. \"10s\"), got %q", name, value)
+	}
+	return d, nil
+}
+
+// boolEnv lee un booleano ("true", "false", "1", "0") o retorna el valor por defecto si no está definido.
+func boolEnv(name string, fallback bool) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean, got %q", name, value)
+	}
+	return b, nil
+}
+
+// intEnv lee un entero no negativo o retorna el valor por defecto si no está definido.
+func intEnv(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", name, value)
+	}
+	return n, nil
+}
+
+// listEnv lee una lista separada por comas, ignorando espacios y elementos vacíos.
+func listEnv(name string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mapEnv lee una lista de pares clave=valor separados por comas.
+func mapEnv(name string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, item := range listEnv(name) {
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("%s entries must look like key=value, got %q", name, item)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// durationMapEnv lee una lista de pares clave=duración separados por comas (ej: "octo-org/api=4h").
+func durationMapEnv(name string) (map[string]time.Duration, error) {
+	values, err := mapEnv(name)
+	if err != nil {
+		return nil, err
+	}
+	durations := make(map[string]time.Duration, len(values))
+	for key, value := range values {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s entry %q must be a positive duration (e.g. \"4h\"), got %q", name, key, value)
+		}
+		durations[key] = d
+	}
+	return durations, nil
+}
+
+// weekdays son las abreviaturas que acepta parseWorkingHours, indexadas por time.Weekday.
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseWorkingHours lee un calendario "<días> <HH:MM>-<HH:MM> [zona]", donde los días son uno
+// ("Mon") o un rango ("Mon-Fri", "Sun-Thu") y la zona es de la base IANA (UTC por defecto).
+func parseWorkingHours(spec string) (WorkingHours, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 1 && strings.EqualFold(fields[0], "always") {
+		return WorkingHours{Location: time.UTC}, nil
+	}
+	if len(fields) < 2 || len(fields) > 3 {
+		return WorkingHours{}, fmt.Errorf("%q must look like \"Mon-Fri 09:00-17:00 UTC\" or \"always\"", spec)
+	}
+
+	var hours WorkingHours
+	first, last, isRange := strings.Cut(strings.ToLower(fields[0]), "-")
+	if !isRange {
+		last = first
+	}
+	from, to := slices.Index(weekdays, first), slices.Index(weekdays, last)
+	if from < 0 || to < 0 {
+		return WorkingHours{}, fmt.Errorf("unknown days %q (use e.g. \"Mon-Fri\")", fields[0])
+	}
+	for day := from; ; day = (day + 1) % 7 {
+		hours.Days[day] = true
+		if day == to {
+			break
+		}
+	}
+
+	start, end, _ := strings.Cut(fields[1], "-")
+	var err error
+	if hours.Start, err = timeOfDay(start); err != nil {
+		return WorkingHours{}, err
+	}
+	if hours.End, err = timeOfDay(end); err != nil {
+		return WorkingHours{}, err
+	}
+	if hours.End <= hours.Start {
+		return WorkingHours{}, fmt.Errorf("working hours %q must end after they start", fields[1])
+	}
+
+	hours.Location = time.UTC
+	if len(fields) == 3 {
+		if hours.Location, err = time.LoadLocation(fields[2]); err != nil {
+			return WorkingHours{}, fmt.Errorf("unknown time zone %q: %w", fields[2], err)
+		}
+	}
+	return hours, nil
+}
+
+// parseQuietHours lee un horario "<HH:MM>-<HH:MM> [zona] [hold|drop]"; puede cruzar la medianoche.
+func parseQuietHours(spec string) (QuietHours, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 3 {
+		return QuietHours{}, fmt.Errorf("%q must look like \"22:00-07:00 America/Mexico_City hold\"", spec)
+	}
+	quiet := QuietHours{Location: time.UTC}
+	start, end, _ := strings.Cut(fields[0], "-")
+	var err error
+	if quiet.Start, err = timeOfDay(start); err != nil {
+		return QuietHours{}, err
+	}
+	if quiet.End, err = timeOfDay(end); err != nil {
+		return QuietHours{}, err
+	}
+	if quiet.Start == quiet.End {
+		return QuietHours{}, fmt.Errorf("quiet hours %q must not start and end at the same time", fields[0])
+	}
+	for _, field := range fields[1:] {
+		switch strings.ToLower(field) {
+		case "hold":
+			quiet.Drop = false
+		case "drop":
+			quiet.Drop = true
+		default:
+			if quiet.Location, err = time.LoadLocation(field); err != nil {
+				return QuietHours{}, fmt.Errorf("unknown time zone %q: %w", field, err)
+			}
+		}
+	}
+	return quiet, nil
+}
+
+// timeOfDay convierte "HH:MM" en el tiempo transcurrido desde la medianoche ("24:00" es el fin del día).
+func timeOfDay(value string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(value, ":")
+	h, errH := strconv.Atoi(hh)
+	m, errM := strconv.Atoi(mm)
+	if !ok || errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time of day %q (use HH:MM)", value)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// Validate revisa valores que LoadConfig acepta pero que fallarían al usarse
+// (URLs mal formadas, nivel de log o exportador desconocido, canales inexistentes).
+func (c *AppConfig) Validate() []error {
+	// Los problemas se reportan siempre en el mismo orden: nada se recorre en el orden de un map
+	var problems []error
+	for _, setting := range []struct{ name, raw string }{
+		{"DISCORD_WEBHOOK_URL_DEVELOPMENT", c.DiscordWebhookURLDevelopment},
+		{"DISCORD_WEBHOOK_URL_TESTING", c.DiscordWebhookURLTesting},
+		{"GITHUB_API_URL", c.GithubAPIURL},
+	} {
+		u, err := url.Parse(setting.raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			problems = append(problems, fmt.Errorf("%s is not a valid http(s) URL", setting.name))
+		}
+	}
+
+	if c.CodeOwnersFile != "" {
+		if _, err := os.Stat(c.CodeOwnersFile); err != nil {
+			problems = append(problems, fmt.Errorf("CODEOWNERS_FILE: %w", err))
+		}
+	}
+
+	switch {
+	case c.GithubAppID != 0 && c.GithubAppPrivateKey == "":
+		problems = append(problems, fmt.Errorf("GITHUB_APP_ID requires GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_FILE"))
+	case c.GithubAppID == 0 && c.GithubAppPrivateKey != "":
+		problems = append(problems, fmt.Errorf("GITHUB_APP_PRIVATE_KEY is set but GITHUB_APP_ID is not"))
+	}
+	// Las instalaciones que cubre la App llegan por el webhook: sin firma cualquiera podría registrarlas
+	if c.GithubAppID != 0 && c.GithubWebhookSecret == "" {
+		problems = append(problems, fmt.Errorf("GITHUB_APP_ID requires GITHUB_WEBHOOK_SECRET: installation events must be signed"))
+	}
+
+	if c.FlakyThreshold > 100 {
+		problems = append(problems, fmt.Errorf("FLAKY_THRESHOLD must be a percentage between 0 and 100, got %d", c.FlakyThreshold))
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.ReportSchedules)) {
+		spec := c.ReportSchedules[name]
+		if spec == "none" {
+			continue
+		}
+		if _, err := cron.ParseStandard(spec); err != nil {
+			problems = append(problems, fmt.Errorf("%s_REPORT_SCHEDULE %q is not a valid cron expression: %w", strings.ToUpper(name), spec, err))
+		}
+	}
+	for _, digest := range c.Digests {
+		if _, err := cron.ParseStandard(digest.Schedule); err != nil {
+			problems = append(problems, fmt.Errorf("DIGEST_%s_SCHEDULE %q is not a valid cron expression: %w", strings.ToUpper(digest.Channel), digest.Schedule, err))
+		}
+		if digest.Period > c.DeliveryRetention {
+			problems = append(problems, fmt.Errorf("DIGEST_%s_PERIOD %s is longer than DELIVERY_RETENTION %s", strings.ToUpper(digest.Channel), digest.Period, c.DeliveryRetention))
+		}
+	}
+	if c.MetricsWindow > c.DeliveryRetention {
+		problems = append(problems, fmt.Errorf("METRICS_WINDOW %s is longer than DELIVERY_RETENTION %s", c.MetricsWindow, c.DeliveryRetention))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		problems = append(problems, fmt.Errorf("LOG_LEVEL %q is not one of debug, info, warn, error", c.LogLevel))
+	}
+
+	switch c.TracesExporter {
+	case "none", "otlp", "stdout":
+	default:
+		problems = append(problems, fmt.Errorf("OTEL_TRACES_EXPORTER %q is not one of none, otlp, stdout", c.TracesExporter))
+	}
+
+	for _, destination := range c.DryRunDestinations {
+		if !slices.Contains(Channels, destination) {
+			problems = append(problems, fmt.Errorf("DRY_RUN_DESTINATIONS contains unknown channel %q", destination))
+		}
+	}
+	for _, destination := range c.PullRequestThreads {
+		if !slices.Contains(Channels, destination) {
+			problems = append(problems, fmt.Errorf("DISCORD_PR_THREADS contains unknown channel %q", destination))
+		}
+	}
+	for _, quiet := range c.QuietHours {
+		// Las claves sin "/" son destinos; con "/" son rutas (evento/acción)
+		if !strings.Contains(quiet.Key, "/") && !slices.Contains(Channels, quiet.Key) {
+			problems = append(problems, fmt.Errorf("QUIET_HOURS contains unknown channel %q", quiet.Key))
+		}
+	}
+	for _, mapping := range []struct {
+		name string
+		ids  map[string]string
+	}{{"DISCORD_USER_MAP", c.DiscordUserIDs}, {"DISCORD_ROLE_MAP", c.DiscordRoleIDs}} {
+		for _, key := range slices.Sorted(maps.Keys(mapping.ids)) {
+			// Los IDs de Discord son snowflakes: enteros sin signo de 64 bits
+			if _, err := strconv.ParseUint(mapping.ids[key], 10, 64); err != nil {
+				problems = append(problems, fmt.Errorf("%s entry %q has invalid Discord ID %q", mapping.name, key, mapping.ids[key]))
+			}
+		}
+	}
+	return problems
+}
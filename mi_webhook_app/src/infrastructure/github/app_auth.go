@@ -0,0 +1,255 @@
+// File: src/infrastructure/github/app_auth.go
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mi_webhook_app/src/application"
+)
+
+// tokenRefreshMargin renueva un token de instalación antes de que expire para que no
+// caduque a mitad de una petición. GitHub los emite con una vigencia de una hora.
+const tokenRefreshMargin = 5 * time.Minute
+
+// errNoInstallation indica que la GitHub App no está instalada en el repositorio consultado.
+var errNoInstallation = errors.New("github app not installed for repository")
+
+// tokenSource entrega las credenciales de cada petición a la API.
+// Un token vacío significa petición anónima.
+type tokenSource interface {
+	token(ctx context.Context, repo string) (string, error)
+}
+
+// staticToken es un token personal o de un bot: el mismo para todos los repositorios.
+type staticToken string
+
+func (t staticToken) token(context.Context, string) (string, error) {
+	return string(t), nil
+}
+
+// installationToken es un token de instalación con su vencimiento.
+type installationToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// appAuth autentica como GitHub App: firma un JWT con la clave privada de la App y lo
+// canjea por un token de la instalación que cubre cada repositorio.
+type appAuth struct {
+	baseURL       string
+	appID         int64
+	key           *rsa.PrivateKey
+	client        *http.Client
+	installations application.InstallationStore // Puede ser nil: se consulta a la API
+	now           func() time.Time
+
+	mu           sync.Mutex
+	tokens       map[int64]installationToken // ID de instalación → token vigente
+	repositories map[string]int64            // "owner/repo" → ID de instalación descubierto por la API
+}
+
+// ParsePrivateKey lee la clave privada PEM de una GitHub App (PKCS#1, como la descarga
+// GitHub, o PKCS#8).
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// NewAppClient crea un cliente que se autentica como la GitHub App appID.
+// Cada petición usa el token de la instalación que cubre el repositorio: la de la entrega
+// en curso si installations confirma que cubre el repositorio, la registrada en installations
+// o la que informe la API.
+func NewAppClient(baseURL string, appID int64, privateKey []byte, installations application.InstallationStore) (*Client, error) {
+	key, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(baseURL, "")
+	client.auth = &appAuth{
+		baseURL:       client.baseURL,
+		appID:         appID,
+		key:           key,
+		client:        client.client,
+		installations: installations,
+		now:           time.Now,
+		tokens:        make(map[int64]installationToken),
+		repositories:  make(map[string]int64),
+	}
+	return client, nil
+}
+
+// token implementa tokenSource. Si la App no está instalada en el repositorio la petición
+// sale anónima: alcanza para repositorios públicos.
+func (a *appAuth) token(ctx context.Context, repo string) (string, error) {
+	id, err := a.installationFor(ctx, repo)
+	if errors.Is(err, errNoInstallation) {
+		application.Logger(ctx).Debug("GitHub App not installed for repository, calling API anonymously", "repo", repo)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	token, err := a.installationToken(ctx, id)
+	if errors.Is(err, errNotFound) {
+		// La instalación ya no existe (ej: la App se desinstaló y se volvió a instalar):
+		// la próxima petición vuelve a preguntar a la API cuál cubre el repositorio
+		a.mu.Lock()
+		delete(a.tokens, id)
+		if a.repositories[repo] == id {
+			delete(a.repositories, repo)
+		}
+		a.mu.Unlock()
+	}
+	return token, err
+}
+
+// installationFor elige la instalación con la que se consulta repo. El ID de instalación de
+// la entrega viene de un payload sin firmar: solo se usa si el registro de instalaciones
+// confirma que cubre repo; si no, cualquiera podría hacer que se emita un token de otra instalación.
+func (a *appAuth) installationFor(ctx context.Context, repo string) (int64, error) {
+	if id, ok := application.InstallationID(ctx); ok && a.installations != nil {
+		installation, found, err := a.installations.GetInstallation(ctx, id)
+		if err == nil && found && !installation.Suspended && installation.Covers(repo) {
+			return id, nil
+		}
+		application.Logger(ctx).Debug("Ignoring unverified installation ID from the delivery", "installation_id", id, "repo", repo)
+	}
+	if a.installations != nil {
+		installation, found, err := a.installations.FindInstallation(ctx, repo)
+		if err != nil {
+			application.Logger(ctx).Warn("Looking up GitHub App installation", "repo", repo, "error", err)
+		} else if found {
+			return installation.ID, nil
+		}
+	}
+
+	a.mu.Lock()
+	id, ok := a.repositories[repo]
+	a.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+	err := a.appRequest(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/installation", repo), &installation)
+	if errors.Is(err, errNotFound) {
+		return 0, fmt.Errorf("%s: %w", repo, errNoInstallation)
+	}
+	if err != nil {
+		return 0, err
+	}
+	a.mu.Lock()
+	a.repositories[repo] = installation.ID
+	a.mu.Unlock()
+	return installation.ID, nil
+}
+
+// installationToken retorna un token vigente de la instalación id, canjeando uno nuevo si hace falta.
+func (a *appAuth) installationToken(ctx context.Context, id int64) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.tokens[id]
+	a.mu.Unlock()
+	if ok && a.now().Add(tokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := a.appRequest(ctx, http.MethodPost, fmt.Sprintf("/app/installations/%d/access_tokens", id), &response); err != nil {
+		return "", fmt.Errorf("failed to create token for installation %d: %w", id, err)
+	}
+	application.Logger(ctx).Debug("Created GitHub App installation token", "installation_id", id, "expires_at", response.ExpiresAt)
+
+	a.mu.Lock()
+	a.tokens[id] = installationToken{value: response.Token, expiresAt: response.ExpiresAt}
+	a.mu.Unlock()
+	return response.Token, nil
+}
+
+// appRequest llama a un endpoint que exige autenticarse como la App (con el JWT).
+func (a *appAuth) appRequest(ctx context.Context, method, path string, out any) error {
+	ctx, span := tracer.Start(ctx, "github.app_auth")
+	defer span.End()
+
+	jwt, err := a.jwt()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error building github request for %s: %w", path, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling github API %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("github API %s: %w", path, errNotFound)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("github API %s failed with status %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding github API %s response: %w", path, err)
+	}
+	return nil
+}
+
+// jwt firma el JWT (RS256) que identifica a la App. Se emite con un minuto de margen hacia
+// atrás por diferencias de reloj y vence a los 9 minutos (GitHub admite como máximo 10).
+func (a *appAuth) jwt() (string, error) {
+	now := a.now()
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign github app JWT: %w", err)
+	}
+	return strings.Join([]string{unsigned, encoding.EncodeToString(signature)}, "."), nil
+}
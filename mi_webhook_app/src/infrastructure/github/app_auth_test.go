@@ -0,0 +1,162 @@
+// File: src/infrastructure/github/app_auth_test.go
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"mi_webhook_app/src/application"
+)
+
+// verifyJWT comprueba la firma RS256 de un JWT de la App y retorna sus claims.
+func verifyJWT(t *testing.T, key *rsa.PublicKey, token string) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed JWT %q", token)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("invalid JWT signature: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestAppClientUsesCachedInstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var lookups, exchanges int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo-org/hello-world/installation", func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		verifyJWT(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		w.Write([]byte(`{"id": 7}`))
+	})
+	mux.HandleFunc("POST /app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		claims := verifyJWT(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if claims["iss"] != "123456" {
+			t.Errorf("unexpected iss claim %v", claims["iss"])
+		}
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, exchanges, expiresAt)
+	})
+	mux.HandleFunc("GET /repos/octo-org/hello-world/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), fmt.Sprintf("Bearer ghs_%d", exchanges); got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+		w.Write([]byte(`{"commits": 1}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewAppClient(server.URL, 123456, privateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := client.PullRequestStats(context.Background(), "octo-org/hello-world", 42); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lookups != 1 || exchanges != 1 {
+		t.Errorf("expected 1 installation lookup and 1 token exchange, got %d and %d", lookups, exchanges)
+	}
+
+	// Cerca del vencimiento se canjea un token nuevo
+	client.auth.(*appAuth).now = func() time.Time { return time.Now().Add(56 * time.Minute) }
+	if _, err := client.PullRequestStats(context.Background(), "octo-org/hello-world", 42); err != nil {
+		t.Fatal(err)
+	}
+	if exchanges != 2 {
+		t.Errorf("expected the token to be renewed, got %d exchanges", exchanges)
+	}
+}
+
+func TestAppClientIgnoresUnverifiedInstallationAndForgetsStaleOnes(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	installationID := 7 // Tras reinstalar la App, GitHub asigna otro ID
+	var exchanged []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo-org/hello-world/installation", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": %d}`, installationID)
+	})
+	mux.HandleFunc("POST /app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		exchanged = append(exchanged, r.PathValue("id"))
+		if r.PathValue("id") != strconv.Itoa(installationID) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"token": "ghs_%s", "expires_at": %q}`, r.PathValue("id"), time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /repos/octo-org/hello-world/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"commits": 1}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewAppClient(server.URL, 123456, privateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// El ID de instalación del payload (sin firmar) no se usa sin confirmarlo
+	ctx := application.WithInstallationID(context.Background(), 99)
+	if _, err := client.PullRequestStats(ctx, "octo-org/hello-world", 42); err != nil {
+		t.Fatal(err)
+	}
+
+	// El token de la instalación 7 vence y su canje falla: la App se reinstaló
+	installationID = 8
+	client.auth.(*appAuth).now = func() time.Time { return time.Now().Add(56 * time.Minute) }
+	if _, err := client.PullRequestStats(context.Background(), "octo-org/hello-world", 42); err == nil {
+		t.Fatal("expected the token exchange of the removed installation to fail")
+	}
+	if _, err := client.PullRequestStats(context.Background(), "octo-org/hello-world", 42); err != nil {
+		t.Fatalf("expected the new installation to be discovered, got %v", err)
+	}
+	if want := []string{"7", "7", "8"}; !slices.Equal(exchanged, want) {
+		t.Errorf("expected token exchanges for %v, got %v", want, exchanged)
+	}
+}
+
+func TestParsePrivateKeyRejectsGarbage(t *testing.T) {
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("expected an error for a non-PEM key")
+	}
+}
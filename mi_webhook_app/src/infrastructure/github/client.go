@@ -0,0 +1,124 @@
+// File: src/infrastructure/github/client.go
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer crea un span por cada petición a la API de GitHub.
+var tracer = otel.Tracer("mi_webhook_app/github")
+
+// defaultRequestTimeout limita cada petición a la API de GitHub.
+const defaultRequestTimeout = 10 * time.Second
+
+// apiVersion es la versión de la API REST que se solicita en cada petición.
+const apiVersion = "2022-11-28"
+
+// errNotFound indica una respuesta 404 de la API.
+var errNotFound = errors.New("github resource not found")
+
+// Client es el cliente REST de GitHub sobre el que se construyen los adaptadores de este paquete.
+type Client struct {
+	baseURL string      // https://api.github.com o la API de un GitHub Enterprise Server
+	auth    tokenSource // Token fijo o GitHub App; sin token: solo repositorios públicos, límite bajo
+	client  *http.Client
+	cache   *etagCache
+}
+
+// NewClient crea un cliente para la API en baseURL que se autentica con token (vacío = anónimo).
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		auth:    staticToken(token),
+		client:  &http.Client{Timeout: defaultRequestTimeout},
+		cache:   newETagCache(defaultCacheEntries),
+	}
+}
+
+// get hace GET a path (relativo a la API) con las credenciales de repo y decodifica la
+// respuesta JSON en out. Las respuestas con ETag se guardan y se revalidan con If-None-Match.
+func (c *Client) get(ctx context.Context, repo, path string, out any) error {
+	ctx, span := tracer.Start(ctx, "github.request", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("github.path", path)))
+	defer span.End()
+
+	req, err := c.newRequest(ctx, repo, path)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "github request not sent")
+		return err
+	}
+	cached, hasCached := c.cache.get(req.URL.String())
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "github request failed")
+		return fmt.Errorf("error calling github API %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		span.SetAttributes(attribute.Bool("github.cache_hit", true))
+		return decodeBody(path, cached.body, out)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("github API %s: %w", path, errNotFound)
+	case resp.StatusCode >= 300:
+		span.SetStatus(codes.Error, resp.Status)
+		application.Logger(ctx).Warn("GitHub API returned non-success status", "path", path, "status", resp.Status)
+		return fmt.Errorf("github API %s failed with status %s", path, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading github API %s response: %w", path, err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.cache.put(req.URL.String(), etag, body)
+	}
+	return decodeBody(path, body, out)
+}
+
+// newRequest arma un GET a path con los headers de la API y las credenciales de repo.
+func (c *Client) newRequest(ctx context.Context, repo, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building github request for %s: %w", path, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	token, err := c.auth.token(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("error authenticating github request for %s: %w", path, err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func decodeBody(path string, body []byte, out any) error {
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding github API %s response: %w", path, err)
+	}
+	return nil
+}
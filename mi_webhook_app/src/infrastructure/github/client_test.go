@@ -0,0 +1,170 @@
+// File: src/infrastructure/github/client_test.go
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeGitHub levanta un stand-in de la API con las rutas indicadas y cuenta las peticiones.
+func fakeGitHub(t *testing.T, routes map[string]http.HandlerFunc) (*Client, *int) {
+	t.Helper()
+	var requests int
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				t.Errorf("missing token on %s", r.URL)
+			}
+			handler(w, r)
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL+"/", "test-token"), &requests
+}
+
+func TestChangedFilesPaginates(t *testing.T) {
+	client, requests := fakeGitHub(t, map[string]http.HandlerFunc{
+		"GET /repos/octo-org/hello-world/pulls/42/files": func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			count := filesPerPage // Primera página llena, segunda con un solo archivo
+			if page == 2 {
+				count = 1
+			}
+			w.Write([]byte("["))
+			for i := range count {
+				if i > 0 {
+					w.Write([]byte(","))
+				}
+				fmt.Fprintf(w, `{"filename": "p%d/f%d.go"}`, page, i)
+			}
+			w.Write([]byte("]"))
+		},
+	})
+
+	files, err := client.ChangedFiles(context.Background(), "octo-org/hello-world", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != filesPerPage+1 || files[len(files)-1] != "p2/f0.go" {
+		t.Errorf("unexpected files (%d): last %q", len(files), files[len(files)-1])
+	}
+	if *requests != 2 {
+		t.Errorf("expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestCodeOwnersSource(t *testing.T) {
+	content := base64.StdEncoding.EncodeToString([]byte("*.go @octo-org/backend\n"))
+	client, requests := fakeGitHub(t, map[string]http.HandlerFunc{
+		"GET /repos/octo-org/hello-world/contents/.github/CODEOWNERS": func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		},
+		"GET /repos/octo-org/hello-world/contents/CODEOWNERS": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"encoding": "base64", "content": "%s\n"}`, content)
+		},
+		"GET /repos/octo-org/empty/contents/": func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		},
+	})
+	source := NewCodeOwnersSource(client, time.Minute)
+
+	for range 2 { // La segunda consulta sale de la caché
+		codeOwners, err := source.CodeOwners(context.Background(), "octo-org/hello-world")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := codeOwners.Owners("cmd/main.go"); !slices.Equal(got, []string{"@octo-org/backend"}) {
+			t.Errorf("unexpected owners %v", got)
+		}
+	}
+	if *requests != 2 {
+		t.Errorf("expected 2 requests (one 404, one hit), got %d", *requests)
+	}
+
+	codeOwners, err := source.CodeOwners(context.Background(), "octo-org/empty")
+	if err != nil || codeOwners != nil {
+		t.Errorf("expected no CODEOWNERS and no error, got %v, %v", codeOwners, err)
+	}
+}
+
+func TestGetRevalidatesWithETag(t *testing.T) {
+	client, requests := fakeGitHub(t, map[string]http.HandlerFunc{
+		"GET /repos/octo-org/hello-world/pulls/42": func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"commits": 3, "additions": 120, "deletions": 14, "changed_files": 4}`))
+		},
+	})
+
+	for range 2 {
+		stats, err := client.PullRequestStats(context.Background(), "octo-org/hello-world", 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Additions != 120 || stats.ChangedFiles != 4 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	}
+	if *requests != 2 {
+		t.Errorf("expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestJobLogsFollowsRedirect(t *testing.T) {
+	client, _ := fakeGitHub(t, map[string]http.HandlerFunc{
+		"GET /repos/octo-org/hello-world/actions/jobs/7/logs": func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/signed/logs/7", http.StatusFound)
+		},
+		"GET /signed/logs/7": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("##[error]Process completed with exit code 1.\n"))
+		},
+	})
+
+	log, err := client.JobLogs(context.Background(), "octo-org/hello-world", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(log) != "##[error]Process completed with exit code 1.\n" {
+		t.Errorf("unexpected log %q", log)
+	}
+}
+
+func TestTailWriterKeepsEnd(t *testing.T) {
+	tail := &tailWriter{limit: 4}
+	for _, chunk := range []string{"abc", "defgh", "ij", "k"} {
+		tail.Write([]byte(chunk))
+	}
+	if got := string(tail.bytes()); got != "hijk" {
+		t.Errorf("tail = %q, want %q", got, "hijk")
+	}
+}
+
+func TestETagCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newETagCache(2)
+	cache.put("a", "1", nil)
+	cache.put("b", "2", nil)
+	cache.get("a") // "b" pasa a ser la menos usada
+	cache.put("c", "3", nil)
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for _, url := range []string{"a", "c"} {
+		if _, ok := cache.get(url); !ok {
+			t.Errorf("expected %s to be cached", url)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// File: src/infrastructure/github/codeowners.go
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"mi_webhook_app/src/application"
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// codeOwnersPaths son las ubicaciones donde GitHub busca CODEOWNERS, en orden de prioridad.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeOwnersSource descarga el CODEOWNERS de la rama por defecto de cada repositorio
+// y lo guarda en memoria durante ttl (también cuando el repositorio no tiene uno).
+type codeOwnersSource struct {
+	client *Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedCodeOwners
+}
+
+type cachedCodeOwners struct {
+	codeOwners *domain.CodeOwners
+	fetchedAt  time.Time
+}
+
+// NewCodeOwnersSource crea un adaptador implementando application.CodeOwnersSource.
+func NewCodeOwnersSource(client *Client, ttl time.Duration) application.CodeOwnersSource {
+	return &codeOwnersSource{client: client, ttl: ttl, cache: make(map[string]cachedCodeOwners)}
+}
+
+// CodeOwners implementa application.CodeOwnersSource.
+func (s *codeOwnersSource) CodeOwners(ctx context.Context, repo string) (*domain.CodeOwners, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[repo]
+	s.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < s.ttl {
+		return cached.codeOwners, nil
+	}
+
+	codeOwners, err := s.fetch(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[repo] = cachedCodeOwners{codeOwners: codeOwners, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return codeOwners, nil
+}
+
+// fetch busca el archivo en cada ubicación; retorna nil si no existe en ninguna.
+func (s *codeOwnersSource) fetch(ctx context.Context, repo string) (*domain.CodeOwners, error) {
+	for _, path := range codeOwnersPaths {
+		var file struct {
+			Content  string `json:"content"`
+			Encoding string `json:"encoding"`
+		}
+		err := s.client.get(ctx, repo, fmt.Sprintf("/repos/%s/contents/%s", repo, path), &file)
+		if errors.Is(err, errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if file.Encoding != "base64" {
+			return nil, fmt.Errorf("unexpected encoding %q for %s/%s", file.Encoding, repo, path)
+		}
+		// GitHub parte el contenido en líneas de 60 caracteres
+		content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s/%s: %w", repo, path, err)
+		}
+		application.Logger(ctx).Debug("Loaded CODEOWNERS from GitHub", "path", path)
+		return domain.ParseCodeOwners(bytes.NewReader(content))
+	}
+	return nil, nil
+}
@@ -0,0 +1,98 @@
+// File: src/infrastructure/github/enrichment.go
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"mi_webhook_app/src/application"
+)
+
+// El cliente implementa los puertos de consulta de la capa de aplicación.
+var (
+	_ application.GitHubClient       = (*Client)(nil)
+	_ application.ChangedFilesSource = (*Client)(nil)
+)
+
+// PullRequestStats implementa application.GitHubClient.
+func (c *Client) PullRequestStats(ctx context.Context, repo string, number int) (*application.PullRequestStats, error) {
+	var pr struct {
+		Commits      int `json:"commits"`
+		Additions    int `json:"additions"`
+		Deletions    int `json:"deletions"`
+		ChangedFiles int `json:"changed_files"`
+	}
+	if err := c.get(ctx, repo, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), &pr); err != nil {
+		return nil, err
+	}
+	return &application.PullRequestStats{
+		Commits:      pr.Commits,
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+	}, nil
+}
+
+// WorkflowRunJobs implementa application.GitHubClient.
+func (c *Client) WorkflowRunJobs(ctx context.Context, repo string, runID int64) ([]application.WorkflowJob, error) {
+	var response struct {
+		Jobs []struct {
+			ID         int64  `json:"id"`
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+			Steps      []struct {
+				Number     int    `json:"number"`
+				Name       string `json:"name"`
+				Conclusion string `json:"conclusion"`
+			} `json:"steps"`
+		} `json:"jobs"`
+	}
+	if err := c.get(ctx, repo, fmt.Sprintf("/repos/%s/actions/runs/%d/jobs?per_page=100", repo, runID), &response); err != nil {
+		return nil, err
+	}
+	jobs := make([]application.WorkflowJob, 0, len(response.Jobs))
+	for _, job := range response.Jobs {
+		steps := make([]application.WorkflowStep, 0, len(job.Steps))
+		for _, step := range job.Steps {
+			steps = append(steps, application.WorkflowStep{Number: step.Number, Name: step.Name, Conclusion: step.Conclusion})
+		}
+		jobs = append(jobs, application.WorkflowJob{
+			ID:         job.ID,
+			Name:       job.Name,
+			Status:     job.Status,
+			Conclusion: job.Conclusion,
+			HTMLURL:    job.HTMLURL,
+			Steps:      steps,
+		})
+	}
+	return jobs, nil
+}
+
+// CheckRuns implementa application.GitHubClient.
+func (c *Client) CheckRuns(ctx context.Context, repo, ref string) ([]application.CheckRun, error) {
+	var response struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	path := fmt.Sprintf("/repos/%s/commits/%s/check-runs?per_page=100", repo, url.PathEscape(ref))
+	if err := c.get(ctx, repo, path, &response); err != nil {
+		return nil, err
+	}
+	checks := make([]application.CheckRun, 0, len(response.CheckRuns))
+	for _, check := range response.CheckRuns {
+		checks = append(checks, application.CheckRun{
+			Name:       check.Name,
+			Status:     check.Status,
+			Conclusion: check.Conclusion,
+			HTMLURL:    check.HTMLURL,
+		})
+	}
+	return checks, nil
+}
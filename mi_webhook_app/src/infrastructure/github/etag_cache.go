@@ -0,0 +1,59 @@
+// File: src/infrastructure/github/etag_cache.go
+package github
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultCacheEntries es el número de respuestas que se guardan para revalidar con ETag.
+const defaultCacheEntries = 512
+
+// etagCache guarda las últimas respuestas por URL junto a su ETag. GitHub responde 304 a una
+// petición con If-None-Match vigente, y esas respuestas no consumen el límite de peticiones.
+// Al llenarse descarta la entrada usada hace más tiempo.
+type etagCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // Más reciente al frente; cada elemento es una *etagEntry
+	entries map[string]*list.Element
+}
+
+type etagEntry struct {
+	url  string
+	etag string
+	body []byte
+}
+
+func newETagCache(max int) *etagCache {
+	return &etagCache{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get retorna la entrada guardada para url.
+func (c *etagCache) get(url string) (etagEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[url]
+	if !ok {
+		return etagEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return *element.Value.(*etagEntry), true
+}
+
+// put guarda (o reemplaza) la respuesta de url.
+func (c *etagCache) put(url, etag string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[url]; ok {
+		element.Value = &etagEntry{url: url, etag: etag, body: body}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[url] = c.order.PushFront(&etagEntry{url: url, etag: etag, body: body})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*etagEntry).url)
+	}
+}
@@ -0,0 +1,79 @@
+// File: src/infrastructure/github/job_logs.go
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"mi_webhook_app/src/application"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxJobLogBytes limita cuánto de un log se conserva: el final, que es donde está el fallo.
+// Queda muy por debajo del límite de adjuntos de Discord para que la descarga y la subida
+// quepan en el plazo de la entrega.
+const maxJobLogBytes = 1 << 20
+
+// JobLogs implementa application.GitHubClient. La API responde con una redirección a una
+// URL firmada y temporal; net/http no reenvía el token al seguirla porque cambia de host.
+func (c *Client) JobLogs(ctx context.Context, repo string, jobID int64) ([]byte, error) {
+	path := fmt.Sprintf("/repos/%s/actions/jobs/%d/logs", repo, jobID)
+	ctx, span := tracer.Start(ctx, "github.request", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("github.path", path)))
+	defer span.End()
+
+	req, err := c.newRequest(ctx, repo, path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "github request failed")
+		return nil, fmt.Errorf("error calling github API %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("github API %s: %w", path, errNotFound)
+	case resp.StatusCode >= 300:
+		span.SetStatus(codes.Error, resp.Status)
+		application.Logger(ctx).Warn("GitHub API returned non-success status", "path", path, "status", resp.Status)
+		return nil, fmt.Errorf("github API %s failed with status %s", path, resp.Status)
+	}
+	tail := &tailWriter{limit: maxJobLogBytes}
+	if _, err := io.Copy(tail, resp.Body); err != nil {
+		return nil, fmt.Errorf("error reading github API %s response: %w", path, err)
+	}
+	return tail.bytes(), nil
+}
+
+// tailWriter conserva los últimos limit bytes escritos. Compacta el buffer solo cuando
+// duplica el límite, así un log enorme no se copia en cada escritura.
+type tailWriter struct {
+	buf   []byte
+	limit int
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > 2*w.limit {
+		w.buf = append(w.buf[:0], w.buf[len(w.buf)-w.limit:]...)
+	}
+	return len(p), nil
+}
+
+func (w *tailWriter) bytes() []byte {
+	if len(w.buf) > w.limit {
+		return w.buf[len(w.buf)-w.limit:]
+	}
+	return w.buf
+}
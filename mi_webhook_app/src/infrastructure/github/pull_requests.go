@@ -0,0 +1,34 @@
+// File: src/infrastructure/github/pull_requests.go
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// Límites de la paginación de archivos: GitHub no lista más de 3000 archivos por pull request.
+const (
+	filesPerPage = 100
+	maxFilePages = 30
+)
+
+// ChangedFiles implementa application.ChangedFilesSource.
+func (c *Client) ChangedFiles(ctx context.Context, repo string, number int) ([]string, error) {
+	var files []string
+	for page := 1; page <= maxFilePages; page++ {
+		var batch []struct {
+			Filename string `json:"filename"`
+		}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/files?per_page=%d&page=%d", repo, number, filesPerPage, page)
+		if err := c.get(ctx, repo, path, &batch); err != nil {
+			return nil, err
+		}
+		for _, file := range batch {
+			files = append(files, file.Filename)
+		}
+		if len(batch) < filesPerPage {
+			break
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,241 @@
+// File: src/infrastructure/handlers/admin_handler.go
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth exige el header "Authorization: Bearer <token>" en las rutas de administración.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !validAdminToken(ctx, token) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Invalid admin token"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// validAdminToken compara en tiempo constante el token Bearer de la petición con token.
+// Un token vacío nunca es válido.
+func validAdminToken(ctx *gin.Context, token string) bool {
+	provided, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+// ListDeliveriesHandler consulta el historial de entregas.
+// Filtros: repo, event, outcome, since y until (RFC3339); paginación con limit y offset.
+func ListDeliveriesHandler(store application.DeliveryStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := application.DeliveryFilter{
+			Repo:    ctx.Query("repo"),
+			Event:   ctx.Query("event"),
+			Outcome: ctx.Query("outcome"),
+		}
+		var err error
+		if filter.Since, err = queryTime(ctx, "since"); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if filter.Until, err = queryTime(ctx, "until"); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if filter.Limit, err = queryInt(ctx, "limit"); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if filter.Offset, err = queryInt(ctx, "offset"); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+
+		records, err := store.ListDeliveries(ctx.Request.Context(), filter)
+		if err != nil {
+			application.Logger(ctx.Request.Context()).Error("Listing deliveries", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error listing deliveries"})
+			return
+		}
+		// El listado no incluye payloads; se obtienen con GET /admin/deliveries/:id
+		for i := range records {
+			records[i].Payload = nil
+		}
+		ctx.JSON(http.StatusOK, gin.H{"deliveries": records, "offset": filter.Offset, "count": len(records)})
+	}
+}
+
+// GetDeliveryHandler retorna una entrega del historial, incluyendo su payload si se guardó.
+func GetDeliveryHandler(store application.DeliveryStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		record, err := store.GetDelivery(ctx.Request.Context(), ctx.Param("id"))
+		if errors.Is(err, application.ErrDeliveryNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Delivery not found"})
+			return
+		}
+		if err != nil {
+			application.Logger(ctx.Request.Context()).Error("Reading delivery", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error reading delivery"})
+			return
+		}
+		ctx.JSON(http.StatusOK, record)
+	}
+}
+
+// queryTime lee un parámetro RFC3339 opcional.
+func queryTime(ctx *gin.Context, name string) (time.Time, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New(name + " must be an RFC3339 timestamp")
+	}
+	return t, nil
+}
+
+// queryInt lee un parámetro entero no negativo opcional.
+func queryInt(ctx *gin.Context, name string) (int, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.New(name + " must be a non-negative integer")
+	}
+	return n, nil
+}
+
+// replayRequest es el cuerpo opcional de POST /admin/deliveries/:id/replay.
+type replayRequest struct {
+	// Destination redirige todas las notificaciones a este canal (ej: "testing").
+	Destination string `json:"destination"`
+	// DryRun renderiza y registra las notificaciones sin publicarlas.
+	DryRun bool `json:"dry_run"`
+}
+
+// ReplayDeliveryHandler vuelve a procesar el payload guardado de una entrega.
+// La reproducción queda registrada en el historial como una entrega nueva con replay_of
+// (salvo en dry-run, que no deja estado persistente). Solo se redirige a uno de destinations.
+func ReplayDeliveryHandler(processor application.WebhookProcessor, store application.DeliveryStore, destinations []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req replayRequest
+		if ctx.Request.ContentLength > 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid replay request body"})
+				return
+			}
+		}
+		if req.Destination != "" && !slices.Contains(destinations, req.Destination) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error",
+				"message": fmt.Sprintf("Unknown destination %q (one of: %s)", req.Destination, strings.Join(destinations, ", "))})
+			return
+		}
+
+		original, err := store.GetDelivery(ctx.Request.Context(), ctx.Param("id"))
+		if errors.Is(err, application.ErrDeliveryNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Delivery not found"})
+			return
+		}
+		if err != nil {
+			application.Logger(ctx.Request.Context()).Error("Reading delivery", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error reading delivery"})
+			return
+		}
+		if len(original.Payload) == 0 {
+			ctx.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Delivery has no stored payload (STORE_RAW_PAYLOADS disabled when it was received)"})
+			return
+		}
+
+		// Con nanosegundos dos reproducciones seguidas no comparten ID (ni registro en el historial)
+		replayID := fmt.Sprintf("replay-%s-%d", original.ID, time.Now().UnixNano())
+		reqCtx := application.WithLogAttrs(ctx.Request.Context(),
+			"delivery_id", replayID, "event", original.Event, "action", original.Action, "replay_of", original.ID)
+		if req.Destination != "" {
+			reqCtx = application.WithDestinationOverride(reqCtx, req.Destination)
+		}
+		if req.DryRun {
+			reqCtx = application.WithDryRun(reqCtx)
+		}
+		application.Logger(reqCtx).Info("Replaying delivery")
+		// Las notificaciones se leen de la captura: una reproducción en dry-run no queda en el historial
+		reqCtx, capture := application.WithNotificationCapture(reqCtx)
+
+		processingErr := processor.Process(reqCtx, application.Event{
+			Name:       original.Event,
+			Action:     original.Action,
+			DeliveryID: replayID,
+			Payload:    original.Payload,
+			ReplayOf:   original.ID,
+		})
+
+		response := gin.H{"replay_delivery_id": replayID, "replay_of": original.ID, "notifications": capture.Records()}
+		switch {
+		case errors.Is(processingErr, application.ErrEventNotHandled):
+			response["status"] = "received"
+			response["message"] = "Event received but type is not handled"
+			ctx.JSON(http.StatusOK, response)
+		case processingErr != nil:
+			application.Logger(reqCtx).Error("Replaying delivery failed", "error", processingErr)
+			response["status"] = "error"
+			response["message"] = processingErr.Error()
+			ctx.JSON(http.StatusBadGateway, response)
+		default:
+			response["status"] = "success"
+			ctx.JSON(http.StatusOK, response)
+		}
+	}
+}
+
+// previewRequest es el cuerpo de POST /admin/preview.
+type previewRequest struct {
+	Event   string          `json:"event" binding:"required"`
+	Payload json.RawMessage `json:"payload" binding:"required"`
+}
+
+// PreviewHandler procesa un payload en dry-run y retorna el enrutamiento y el JSON exacto
+// que se enviaría a Discord. No publica nada ni lo registra en el historial.
+func PreviewHandler(processor application.WebhookProcessor) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req previewRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Body must be {\"event\": ..., \"payload\": {...}}"})
+			return
+		}
+
+		reqCtx := application.WithLogAttrs(ctx.Request.Context(), "delivery_id", "preview", "event", req.Event)
+		reqCtx = application.WithoutDeliveryRecord(application.WithDryRun(reqCtx))
+		reqCtx, capture := application.WithNotificationCapture(reqCtx)
+		processingErr := processor.Process(reqCtx, application.Event{
+			Name:       req.Event,
+			DeliveryID: "preview",
+			Payload:    req.Payload,
+		})
+
+		route, handled := capture.Route()
+		response := gin.H{
+			"handled":       handled,
+			"route":         route,
+			"notifications": capture.Records(),
+		}
+		if processingErr != nil && !errors.Is(processingErr, application.ErrEventNotHandled) {
+			response["error"] = processingErr.Error()
+			ctx.JSON(http.StatusUnprocessableEntity, response)
+			return
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,39 @@
+// File: src/infrastructure/handlers/metrics_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"mi_webhook_app/src/application"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LifecycleMetricsHandler retorna las métricas del ciclo de vida de pull requests y deployments.
+// Filtros: repo, since y until (RFC3339); sin since ni until cubre los últimos 30 días.
+func LifecycleMetricsHandler(source application.LifecycleMetricsSource) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := application.MetricsFilter{Repo: ctx.Query("repo")}
+		var err error
+		if filter.Since, err = queryTime(ctx, "since"); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if filter.Until, err = queryTime(ctx, "until"); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "since must be before until"})
+			return
+		}
+
+		metrics, err := source.LifecycleMetrics(ctx.Request.Context(), filter)
+		if err != nil {
+			application.Logger(ctx.Request.Context()).Error("Computing lifecycle metrics", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error computing metrics"})
+			return
+		}
+		ctx.JSON(http.StatusOK, metrics)
+	}
+}
@@ -0,0 +1,43 @@
+// File: src/infrastructure/handlers/readiness.go
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Readiness indica si el servidor debe seguir recibiendo tráfico.
+// Se marca como no listo al iniciar el apagado para que el balanceador deje de enviar peticiones.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// NewReadiness crea un indicador de readiness en estado listo.
+func NewReadiness() *Readiness {
+	r := &Readiness{}
+	r.ready.Store(true)
+	return r
+}
+
+// SetReady cambia el estado de readiness.
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// IsReady retorna el estado actual.
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
+
+// ReadinessHandler responde 200 mientras el servidor está listo y 503 durante el apagado.
+func ReadinessHandler(readiness *Readiness) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !readiness.IsReady() {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "SHUTTING_DOWN"})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"status": "READY"})
+	}
+}
@@ -0,0 +1,26 @@
+// File: src/infrastructure/handlers/request_logger.go
+package handlers
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger reemplaza el logger de texto de Gin por una línea slog por petición.
+// No registra la query string para no filtrar parámetros sensibles.
+func RequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		slog.Info("HTTP request",
+			"method", ctx.Request.Method,
+			"path", ctx.Request.URL.Path,
+			"status", ctx.Writer.Status(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", ctx.ClientIP(),
+			"delivery_id", ctx.GetHeader("X-GitHub-Delivery"),
+		)
+	}
+}
@@ -2,104 +2,177 @@
 package handlers
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	// --- IMPORTACIÓN ACTUALIZADA (usa tu nombre de módulo) ---
 	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/metrics"
 	// --- FIN IMPORTACIÓN ACTUALIZADA ---
 
 	"github.com/gin-gonic/gin"
-	// Descomenta para verificación de firma
-	// "crypto/hmac"
-	// "crypto/sha256"
-	// "encoding/hex"
-	// "strings"
-	// "mi_webhook_app/src/infrastructure/config" // Necesitarías config si verificas firma
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer crea el span raíz de cada entrega de webhook.
+var tracer = otel.Tracer("mi_webhook_app/handlers")
+
 // GithubWebhookHandler crea una función manejadora de Gin.
 // Depende del servicio de aplicación (procesador de casos de uso) a través de su interfaz de puerto.
-// Si necesitas verificación de firma, también necesitarías inyectar *config.AppConfig aquí.
-func GithubWebhookHandler(processor application.WebhookProcessor /* , cfg *config.AppConfig */) gin.HandlerFunc {
+// adminToken autoriza el header X-Dry-Run; vacío = el header no se acepta.
+// secret verifica la firma X-Hub-Signature-256 de cada entrega; vacío = no se verifica.
+func GithubWebhookHandler(processor application.WebhookProcessor, adminToken, secret string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Headers estándar de GitHub
 		eventType := ctx.GetHeader("X-GitHub-Event")
 		deliveryID := ctx.GetHeader("X-GitHub-Delivery")
-		// signature := ctx.GetHeader("X-Hub-Signature-256") // Para verificación
+		signature := ctx.GetHeader("X-Hub-Signature-256")
+
+		// Cada línea de log de esta entrega lleva delivery_id, event y action (y luego repo y destination)
+		reqCtx := application.WithLogAttrs(ctx.Request.Context(), "delivery_id", deliveryID, "event", eventType)
+		// Span raíz de la entrega; decodificación, enrutamiento, render y envíos cuelgan de él
+		reqCtx, span := tracer.Start(reqCtx, "webhook.receive", trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(application.AttrEvent.String(eventType), application.AttrDeliveryID.String(deliveryID)))
+		defer span.End()
+		application.Logger(reqCtx).Info("Webhook received")
 
-		log.Printf("INFO: Webhook received: Event=%s, DeliveryID=%s", eventType, deliveryID)
+		start := time.Now()
+		// Las etiquetas de las métricas solo toman eventos y acciones con rutas registradas
+		eventLabel, _ := metrics.DeliveryLabels(eventType, "")
+		metrics.InFlightDeliveries.Inc()
+		defer func() {
+			metrics.InFlightDeliveries.Dec()
+			metrics.HandlerDuration.WithLabelValues(eventLabel).Observe(time.Since(start).Seconds())
+		}()
 
 		// Leer payload crudo
 		payload, err := ctx.GetRawData()
 		if err != nil {
-			log.Printf("ERROR: Reading request body: %v", err)
+			application.Logger(reqCtx).Error("Reading request body", "error", err)
+			span.SetStatus(codes.Error, "error reading request body")
+			metrics.DeliveriesTotal.WithLabelValues(eventLabel, "", application.OutcomeError).Inc()
 			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Error reading request body"})
 			return
 		}
 
-		// --- Verificación de Firma (Opcional pero Recomendado) ---
-		/*
-		   if cfg.GithubWebhookSecret != "" {
-		       if !isValidSignature(signature, cfg.GithubWebhookSecret, payload) {
-		           log.Printf("WARNING: Invalid webhook signature. DeliveryID: %s", deliveryID)
-		           ctx.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Invalid signature"})
-		           return
-		       }
-		       log.Println("INFO: Webhook signature verified.")
-		   } else {
-		       log.Println("WARNING: Webhook signature verification skipped (GITHUB_WEBHOOK_SECRET not set).")
-		   }
-		*/
-
-		var processingErr error // Para capturar error de la capa de aplicación
-
-		// Dirige al método apropiado del servicio de aplicación basado en el evento
-		switch eventType {
-		case "pull_request":
-			log.Printf("INFO: Processing 'pull_request' event...")
-			// Llama al servicio de aplicación a través del puerto de interfaz
-			processingErr = processor.ProcessPullRequestEvent(payload)
-		case "workflow_run":
-			log.Printf("INFO: Processing 'workflow_run' event...")
-			// Llama al servicio de aplicación a través del puerto de interfaz
-			processingErr = processor.ProcessWorkflowRunEvent(payload)
-		// Añade más casos aquí si manejas otros eventos (ej: push, issues)
-		default:
-			// Evento recibido pero no manejado por esta aplicación
-			log.Printf("INFO: Ignoring unhandled event type: %s", eventType)
-			ctx.JSON(http.StatusOK, gin.H{"status": "received", "message": "Event received but type is not handled"})
+		// --- Verificación de Firma ---
+		// Con secreto, una entrega sin firma válida no llega al servicio: podría registrar instalaciones
+		// de la GitHub App y hacer que el servicio consulte repositorios privados por su cuenta.
+		if secret != "" {
+			if !isValidSignature(signature, secret, payload) {
+				application.Logger(reqCtx).Warn("Invalid webhook signature")
+				span.SetStatus(codes.Error, "invalid signature")
+				metrics.SignatureFailuresTotal.WithLabelValues(eventLabel).Inc()
+				metrics.DeliveriesTotal.WithLabelValues(eventLabel, "", application.OutcomeError).Inc()
+				ctx.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Invalid signature"})
+				return
+			}
+			application.Logger(reqCtx).Debug("Webhook signature verified")
+		} else {
+			application.Logger(reqCtx).Debug("Webhook signature verification skipped (GITHUB_WEBHOOK_SECRET not set)")
+		}
+
+		// El servicio de aplicación despacha por tipo de evento y acción a través del puerto;
+		// este adaptador no necesita conocer los eventos individuales.
+		action := payloadAction(payload)
+		reqCtx = application.WithLogAttrs(reqCtx, "action", action)
+		span.SetAttributes(application.AttrAction.String(action))
+		_, actionLabel := metrics.DeliveryLabels(eventType, action)
+		if dryRun, _ := strconv.ParseBool(ctx.GetHeader("X-Dry-Run")); dryRun {
+			// Permite probar una entrega manualmente sin publicar en Discord. El endpoint no está
+			// firmado: solo quien tiene el token de administración puede pedirlo.
+			if !validAdminToken(ctx, adminToken) {
+				application.Logger(reqCtx).Warn("Rejecting X-Dry-Run without a valid admin token")
+				metrics.DeliveriesTotal.WithLabelValues(eventLabel, actionLabel, application.OutcomeError).Inc()
+				ctx.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "X-Dry-Run requires the admin token"})
+				return
+			}
+			reqCtx = application.WithDryRun(reqCtx)
+		}
+		// La captura guarda el enrutamiento y los payloads renderizados; en dry-run van en la respuesta
+		reqCtx, capture := application.WithNotificationCapture(reqCtx)
+		respond := func(status int, body gin.H) {
+			if application.IsDryRun(reqCtx) {
+				route, handled := capture.Route()
+				body["dry_run"], body["handled"], body["route"], body["notifications"] = true, handled, route, capture.Records()
+			}
+			ctx.JSON(status, body)
+		}
+		application.Logger(reqCtx).Info("Processing event")
+		processingErr := processor.Process(reqCtx, application.Event{
+			Name:       eventType,
+			Action:     action,
+			DeliveryID: deliveryID,
+			Payload:    payload,
+		})
+
+		if errors.Is(processingErr, application.ErrEventNotHandled) {
+			// Evento recibido pero no manejado por esta aplicación. Incluye las acciones sin manejador de
+			// eventos conocidos (ej: pull_request "labeled"), que antes del registro respondían "success".
+			application.Logger(reqCtx).Info("Ignoring unhandled event type")
+			metrics.DeliveriesTotal.WithLabelValues(eventLabel, actionLabel, application.OutcomeIgnored).Inc()
+			respond(http.StatusOK, gin.H{"status": "received", "message": "Event received but type is not handled"})
 			return // Importante retornar aquí para no seguir a la lógica de error/éxito
 		}
+		if errors.Is(processingErr, application.ErrDuplicateDelivery) {
+			// GitHub reenvió una entrega ya procesada: se confirma sin volver a notificar
+			metrics.DuplicateDeliveriesTotal.WithLabelValues(eventLabel).Inc()
+			metrics.DeliveriesTotal.WithLabelValues(eventLabel, actionLabel, application.OutcomeIgnored).Inc()
+			respond(http.StatusOK, gin.H{"status": "received", "message": "Delivery already processed"})
+			return
+		}
 
 		// Responde a GitHub basado en el resultado de la lógica de aplicación
 		if processingErr != nil {
 			// Loguea el error específico de la capa de aplicación
-			log.Printf("ERROR: Processing event '%s': %v", eventType, processingErr)
+			application.Logger(reqCtx).Error("Processing event failed", "error", processingErr)
+			span.RecordError(processingErr)
+			span.SetStatus(codes.Error, "error processing event")
+			metrics.DeliveriesTotal.WithLabelValues(eventLabel, actionLabel, application.OutcomeError).Inc()
 			// Retorna un error genérico del servidor al cliente (GitHub)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": fmt.Sprintf("Error processing event '%s'", eventType)})
+			respond(http.StatusInternalServerError, gin.H{"status": "error", "message": fmt.Sprintf("Error processing event '%s'", eventType)})
 		} else {
 			// Éxito en el procesamiento (incluso si no se envió notificación por lógica interna)
-			log.Printf("INFO: Event '%s' processed successfully. DeliveryID: %s", eventType, deliveryID)
-			ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": fmt.Sprintf("Event '%s' processed successfully", eventType)})
+			application.Logger(reqCtx).Info("Event processed successfully")
+			metrics.DeliveriesTotal.WithLabelValues(eventLabel, actionLabel, application.OutcomeProcessed).Inc()
+			respond(http.StatusOK, gin.H{"status": "success", "message": fmt.Sprintf("Event '%s' processed successfully", eventType)})
 		}
 	}
 }
 
-/* // Descomenta y ajusta si usas verificación de firma
+// payloadAction extrae el campo "action" del payload para etiquetar métricas.
+// Retorna vacío si el payload no es JSON válido; el servicio reportará el error.
+func payloadAction(payload []byte) string {
+	var envelope struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return ""
+	}
+	return envelope.Action
+}
+
+// isValidSignature compara la firma "sha256=<hex>" de GitHub con el HMAC-SHA256 del payload crudo.
 func isValidSignature(ghSignature, secret string, payload []byte) bool {
 	if secret == "" { // Doble chequeo por si acaso
 		return false // No se puede validar sin secreto
 	}
-	if !strings.HasPrefix(ghSignature, "sha256=") {
-		log.Println("ERROR: Signature format invalid (missing sha256= prefix)")
+	expectedSigHex, ok := strings.CutPrefix(ghSignature, "sha256=")
+	if !ok {
 		return false
 	}
-	expectedSigHex := strings.TrimPrefix(ghSignature, "sha256=")
 	expectedSig, err := hex.DecodeString(expectedSigHex)
 	if err != nil {
-		log.Printf("ERROR: Failed to decode expected signature hex: %v", err)
 		return false
 	}
 
@@ -109,5 +182,4 @@ func isValidSignature(ghSignature, secret string, payload []byte) bool {
 
 	// Compara en tiempo constante para evitar ataques de temporización
 	return hmac.Equal(calculatedSig, expectedSig)
-}
-*/
\ No newline at end of file
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+// File: src/infrastructure/logging/logging.go
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// redacted reemplaza cualquier secreto que aparezca en un log.
+const redacted = "[REDACTED]"
+
+var (
+	level = new(slog.LevelVar) // Nivel global; se puede cambiar después de cargar la configuración
+
+	secretsMu sync.RWMutex
+	secrets   []string // Valores que nunca deben aparecer en los logs (URLs de webhook, secretos)
+)
+
+// Init instala un logger JSON de slog que escribe en w como logger por defecto.
+// Los logs del paquete log estándar también pasan por él.
+func Init(w io.Writer) {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+	slog.SetDefault(slog.New(handler))
+}
+
+// SetLevel cambia el nivel de log ("debug", "info", "warn" o "error").
+func SetLevel(name string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+	level.Set(l)
+	return nil
+}
+
+// RegisterSecret añade un valor que se reemplazará por [REDACTED] en cualquier atributo de log.
+func RegisterSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = append(secrets, secret)
+}
+
+// RedactURL oculta el token de una URL de webhook de Discord (/api/webhooks/{id}/{token})
+// y cualquier query string, dejando el host y el ID para poder identificar el destino.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		if segment == "webhooks" && i+2 < len(segments) {
+			segments[i+2] = "REDACTED" // Sin corchetes para que la URL no quede escapada
+			u.Path = "/" + strings.Join(segments, "/")
+			break
+		}
+	}
+	u.RawQuery = ""
+	u.User = nil
+	return u.String()
+}
+
+// redactAttr reemplaza los secretos registrados dentro de valores string y de error.
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(redactString(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			attr.Value = slog.StringValue(redactString(err.Error()))
+		}
+	}
+	return attr
+}
+
+func redactString(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+// File: src/infrastructure/metrics/held.go
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// heldTimeout limita cada lectura de las notificaciones retenidas.
+const heldTimeout = 10 * time.Second
+
+var heldNotificationsDesc = prometheus.NewDesc("webhook_held_notifications",
+	"Notifications held by quiet hours, by state (pending release or dead_letter after repeated release failures).",
+	[]string{"state"}, nil)
+
+// HeldNotificationsCollector exporta a Prometheus cuántas notificaciones retenidas por el horario
+// de silencio esperan su publicación y cuántas se apartaron como dead letter. Se registra con
+// prometheus.MustRegister.
+type HeldNotificationsCollector struct {
+	store application.HeldNotificationStore
+}
+
+// NewHeldNotificationsCollector crea el colector sobre store.
+func NewHeldNotificationsCollector(store application.HeldNotificationStore) *HeldNotificationsCollector {
+	return &HeldNotificationsCollector{store: store}
+}
+
+// Describe implementa prometheus.Collector.
+func (c *HeldNotificationsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- heldNotificationsDesc
+}
+
+// Collect implementa prometheus.Collector.
+func (c *HeldNotificationsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), heldTimeout)
+	defer cancel()
+	held, err := c.store.ListHeldNotifications(ctx)
+	if err != nil {
+		slog.Error("Listing held notifications for metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(heldNotificationsDesc, err)
+		return
+	}
+	var pending, deadLetter int
+	for _, n := range held {
+		if n.DeadLetter {
+			deadLetter++
+		} else {
+			pending++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(heldNotificationsDesc, prometheus.GaugeValue, float64(pending), "pending")
+	ch <- prometheus.MustNewConstMetric(heldNotificationsDesc, prometheus.GaugeValue, float64(deadLetter), "dead_letter")
+}
@@ -0,0 +1,113 @@
+// File: src/infrastructure/metrics/lifecycle.go
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// lifecycleCacheTTL es cuánto se reutilizan las métricas calculadas: cada cálculo lee el
+// historial de entregas del período completo y Prometheus consulta cada pocos segundos.
+const lifecycleCacheTTL = 5 * time.Minute
+
+// lifecycleTimeout limita cada cálculo de las métricas.
+const lifecycleTimeout = 30 * time.Second
+
+var (
+	prMergedDesc = prometheus.NewDesc("github_pull_requests_merged",
+		"Pull requests merged in the metrics window.", nil, nil)
+	prFirstReviewDesc = prometheus.NewDesc("github_pull_request_time_to_first_review_seconds",
+		"Time from pull request creation to its first review, for pull requests merged in the metrics window.", nil, nil)
+	prMergeDesc = prometheus.NewDesc("github_pull_request_time_to_merge_seconds",
+		"Time from pull request creation to merge, for pull requests merged in the metrics window.", nil, nil)
+	prIterationsDesc = prometheus.NewDesc("github_pull_request_review_iterations",
+		"Review rounds (1 + changes requested, 0 without review) of pull requests merged in the metrics window.", nil, nil)
+	prSizeDesc = prometheus.NewDesc("github_pull_request_size_lines",
+		"Lines added plus deleted by pull requests merged in the metrics window.", nil, nil)
+	deploymentsDesc = prometheus.NewDesc("github_deployments",
+		"Finished deployments in the metrics window, by outcome (success or failure).", []string{"outcome"}, nil)
+	deployFrequencyDesc = prometheus.NewDesc("github_deployments_per_day",
+		"Successful deployments per day in the metrics window.", nil, nil)
+	leadTimeDesc = prometheus.NewDesc("github_deployment_lead_time_seconds",
+		"Time from pull request creation to its first successful deployment, in the metrics window.", nil, nil)
+	changeFailureDesc = prometheus.NewDesc("github_change_failure_ratio",
+		"Failed deployments over finished deployments in the metrics window.", nil, nil)
+)
+
+// LifecycleCollector exporta a Prometheus las métricas del ciclo de vida de pull requests y
+// deployments de los últimos window. Se registra con prometheus.MustRegister.
+type LifecycleCollector struct {
+	source application.LifecycleMetricsSource
+	window time.Duration
+
+	mu         sync.Mutex
+	cached     application.LifecycleMetrics
+	computedAt time.Time
+}
+
+// NewLifecycleCollector crea el colector sobre source.
+func NewLifecycleCollector(source application.LifecycleMetricsSource, window time.Duration) *LifecycleCollector {
+	return &LifecycleCollector{source: source, window: window}
+}
+
+// Describe implementa prometheus.Collector.
+func (c *LifecycleCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{prMergedDesc, prFirstReviewDesc, prMergeDesc, prIterationsDesc, prSizeDesc,
+		deploymentsDesc, deployFrequencyDesc, leadTimeDesc, changeFailureDesc} {
+		ch <- desc
+	}
+}
+
+// Collect implementa prometheus.Collector.
+func (c *LifecycleCollector) Collect(ch chan<- prometheus.Metric) {
+	m, err := c.metrics()
+	if err != nil {
+		slog.Error("Computing lifecycle metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(prMergedDesc, err)
+		return
+	}
+	prs, deployments := m.PullRequests, m.Deployments
+	ch <- prometheus.MustNewConstMetric(prMergedDesc, prometheus.GaugeValue, float64(prs.Merged))
+	ch <- distributionMetric(prFirstReviewDesc, prs.TimeToFirstReview)
+	ch <- distributionMetric(prMergeDesc, prs.TimeToMerge)
+	ch <- distributionMetric(prIterationsDesc, prs.ReviewIterations)
+	ch <- distributionMetric(prSizeDesc, prs.Size)
+	ch <- prometheus.MustNewConstMetric(deploymentsDesc, prometheus.GaugeValue, float64(deployments.Successful), "success")
+	ch <- prometheus.MustNewConstMetric(deploymentsDesc, prometheus.GaugeValue, float64(deployments.Failed), "failure")
+	ch <- prometheus.MustNewConstMetric(deployFrequencyDesc, prometheus.GaugeValue, deployments.PerDay)
+	ch <- distributionMetric(leadTimeDesc, deployments.LeadTime)
+	ch <- prometheus.MustNewConstMetric(changeFailureDesc, prometheus.GaugeValue, deployments.ChangeFailureRate)
+}
+
+// metrics retorna las métricas en caché o las recalcula si vencieron.
+func (c *LifecycleCollector) metrics() (application.LifecycleMetrics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.computedAt.IsZero() && time.Since(c.computedAt) < lifecycleCacheTTL {
+		return c.cached, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	now := time.Now().UTC()
+	m, err := c.source.LifecycleMetrics(ctx, application.MetricsFilter{Since: now.Add(-c.window), Until: now})
+	if err != nil {
+		return application.LifecycleMetrics{}, err
+	}
+	c.cached, c.computedAt = m, now
+	return m, nil
+}
+
+// distributionMetric exporta una distribución como un summary con la mediana y el percentil 90.
+func distributionMetric(desc *prometheus.Desc, d application.Distribution) prometheus.Metric {
+	quantiles := map[float64]float64{}
+	if d.Count > 0 {
+		quantiles[0.5], quantiles[0.9] = d.Median, d.P90
+	}
+	return prometheus.MustNewConstSummary(desc, uint64(d.Count), d.Mean*float64(d.Count), quantiles)
+}
@@ -0,0 +1,118 @@
+// File: src/infrastructure/metrics/metrics.go
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Resultados posibles de la publicación de un lote de notificaciones agrupadas.
+const (
+	BatchSent   = "sent"
+	BatchFailed = "failed"
+)
+
+// OtherLabel reemplaza los eventos y acciones que no son rutas registradas. El evento viene de un
+// header y la acción del payload: sin este límite cualquier cliente crearía series sin fin.
+const OtherLabel = "other"
+
+var (
+	// DeliveriesTotal cuenta las entregas recibidas por evento, acción y resultado
+	// (application.OutcomeProcessed, OutcomeIgnored u OutcomeError).
+	DeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "GitHub webhook deliveries received, by event, action and outcome.",
+	}, []string{"event", "action", "outcome"})
+
+	// SignatureFailuresTotal cuenta las entregas rechazadas por no tener una firma válida.
+	SignatureFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_signature_failures_total",
+		Help: "GitHub webhook deliveries rejected for a missing or invalid signature, by event.",
+	}, []string{"event"})
+
+	// DuplicateDeliveriesTotal cuenta las entregas que ya se habían procesado (mismo X-GitHub-Delivery).
+	DuplicateDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_duplicate_deliveries_total",
+		Help: "GitHub webhook deliveries skipped because the same delivery ID was already processed, by event.",
+	}, []string{"event"})
+
+	// HandlerDuration mide cuánto tarda el handler HTTP en procesar una entrega.
+	HandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "webhook_handler_duration_seconds",
+		Help:    "Time spent handling a GitHub webhook delivery.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"event"})
+
+	// InFlightDeliveries es el número de entregas en proceso (lo que el apagado debe drenar).
+	InFlightDeliveries = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "webhook_in_flight_deliveries",
+		Help: "Webhook deliveries currently being processed.",
+	})
+
+	// NotificationsTotal cuenta los envíos a Discord por destino y código de estado HTTP.
+	NotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "discord_notifications_total",
+		Help: "Discord webhook posts, by destination and HTTP status code (\"error\" if no response).",
+	}, []string{"destination", "status_code"})
+
+	// CoalescedNotificationsTotal cuenta las notificaciones retenidas para agruparlas según cómo
+	// terminó la publicación de su lote. La entrega ya quedó registrada como procesada: un lote
+	// fallido solo se ve aquí y en el log.
+	CoalescedNotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "discord_coalesced_notifications_total",
+		Help: "Notifications held for coalescing, by destination and outcome of the batch post (\"sent\" or \"failed\").",
+	}, []string{"destination", "outcome"})
+
+	// CoalescingQueueDepth es el número de notificaciones retenidas esperando que venza su ventana de agrupación.
+	CoalescingQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "discord_coalescing_queue_depth",
+		Help: "Notifications held for coalescing and not yet posted, by destination.",
+	}, []string{"destination"})
+
+	// DiscordRequestDuration mide el tiempo de ida y vuelta de cada petición a Discord.
+	DiscordRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "discord_request_duration_seconds",
+		Help:    "Round-trip time of Discord webhook requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"destination"})
+)
+
+// ObserveNotification registra un envío a Discord. statusCode 0 indica que no hubo respuesta.
+func ObserveNotification(destination string, statusCode int, elapsed time.Duration) {
+	code := "error"
+	if statusCode > 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	NotificationsTotal.WithLabelValues(destination, code).Inc()
+	DiscordRequestDuration.WithLabelValues(destination).Observe(elapsed.Seconds())
+}
+
+// knownRoutes son las rutas registradas (ej: "pull_request/opened") y sus eventos (ej: "pull_request/").
+var knownRoutes = sync.OnceValue(func() map[string]bool {
+	known := make(map[string]bool)
+	for _, route := range application.RegisteredRoutes() {
+		event, _, _ := strings.Cut(route, "/")
+		known[route], known[event+"/"] = true, true
+	}
+	return known
+})
+
+// DeliveryLabels acota las etiquetas event y action de una entrega: un evento sin rutas registradas
+// pasa a OtherLabel, y también la acción si no tiene una ruta exacta.
+func DeliveryLabels(event, action string) (string, string) {
+	known := knownRoutes()
+	if !known[event+"/"] {
+		return OtherLabel, OtherLabel
+	}
+	if action != "" && !known[event+"/"+action] {
+		action = OtherLabel
+	}
+	return event, action
+}
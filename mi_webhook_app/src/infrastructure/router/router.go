@@ -2,29 +2,71 @@
 package router
 
 import (
+	"log/slog"
+
 	// --- IMPORTACIONES ACTUALIZADAS (usa tu nombre de módulo) ---
 	"mi_webhook_app/src/application"
 	"mi_webhook_app/src/infrastructure/handlers"
 	// --- FIN IMPORTACIONES ACTUALIZADAS ---
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Dependencies agrupa lo que las rutas inyectan en sus manejadores.
+type Dependencies struct {
+	// Processor es el servicio de aplicación (puerto application.WebhookProcessor).
+	Processor application.WebhookProcessor
+	// Readiness se apaga desde main al recibir una señal de terminación.
+	Readiness *handlers.Readiness
+	// Deliveries es el historial de entregas; nil deshabilita su API.
+	Deliveries application.DeliveryStore
+	// Destinations son los canales configurados a los que se puede redirigir una reproducción.
+	Destinations []string
+	// Metrics calcula las métricas del ciclo de vida; nil deshabilita su API.
+	Metrics application.LifecycleMetricsSource
+	// AdminToken protege las rutas /admin; si está vacío no se registran.
+	AdminToken string
+	// WebhookSecret verifica la firma de las entregas; si está vacío no se verifican.
+	WebhookSecret string
+}
+
 // SetupRoutes configura el motor Gin.
 // Recibe el servicio de aplicación (a través de su puerto) para inyectarlo en el manejador.
-func SetupRoutes(engine *gin.Engine, processor application.WebhookProcessor) {
+func SetupRoutes(engine *gin.Engine, deps Dependencies) {
 
 	// Endpoint base para los webhooks entrantes
 	webhookGroup := engine.Group("/webhook")
 	{
 		// Un único endpoint para recibir todos los webhooks de GitHub
 		// Pasa el servicio de aplicación (processor) a la fábrica de manejadores.
-		// Si necesitaras inyectar config al handler (ej: para firma), lo harías aquí.
-		webhookGroup.POST("/github", handlers.GithubWebhookHandler(processor /*, cfg */))
+		webhookGroup.POST("/github", handlers.GithubWebhookHandler(deps.Processor, deps.AdminToken, deps.WebhookSecret))
 	}
 
 	// Endpoint opcional de health check
 	engine.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "UP"})
 	})
+
+	// Readiness: falla durante el apagado para dejar de recibir tráfico nuevo
+	engine.GET("/ready", handlers.ReadinessHandler(deps.Readiness))
+
+	// Métricas de Prometheus
+	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// API de administración, protegida por token
+	if deps.AdminToken == "" {
+		slog.Warn("ADMIN_TOKEN not set, admin API disabled")
+		return
+	}
+	adminGroup := engine.Group("/admin", handlers.AdminAuth(deps.AdminToken))
+	adminGroup.POST("/preview", handlers.PreviewHandler(deps.Processor))
+	if deps.Deliveries != nil {
+		adminGroup.GET("/deliveries", handlers.ListDeliveriesHandler(deps.Deliveries))
+		adminGroup.GET("/deliveries/:id", handlers.GetDeliveryHandler(deps.Deliveries))
+		adminGroup.POST("/deliveries/:id/replay", handlers.ReplayDeliveryHandler(deps.Processor, deps.Deliveries, deps.Destinations))
+	}
+	if deps.Metrics != nil {
+		adminGroup.GET("/metrics", handlers.LifecycleMetricsHandler(deps.Metrics))
+	}
 }
\ No newline at end of file
@@ -0,0 +1,65 @@
+// File: src/infrastructure/scheduler/scheduler.go
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Disabled deshabilita un reporte en lugar de una expresión cron.
+const Disabled = "none"
+
+// reportTimeout limita cada ejecución de un reporte.
+const reportTimeout = 2 * time.Minute
+
+// Scheduler dispara los reportes periódicos del servicio según expresiones cron.
+type Scheduler struct {
+	cron *cron.Cron
+}
+
+// New programa cada reporte habilitado en publisher con su expresión de schedules
+// (formato cron estándar; "CRON_TZ=America/Mexico_City 0 9 * * 1" fija la zona horaria).
+// Un reporte sin expresión o con Disabled no se programa.
+func New(publisher application.ReportPublisher, schedules map[string]string) (*Scheduler, error) {
+	// Si un reporte sigue en curso cuando toca el siguiente, el siguiente se salta
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	for _, name := range publisher.Reports() {
+		spec := schedules[name]
+		if spec == "" || spec == Disabled {
+			slog.Info("Report not scheduled", "report", name)
+			continue
+		}
+		_, err := c.AddFunc(spec, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+			defer cancel()
+			// PublishReport ya registra el error; el siguiente disparo lo reintenta
+			_ = publisher.PublishReport(ctx, name)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q for report %s: %w", spec, name, err)
+		}
+		slog.Info("Report scheduled", "report", name, "schedule", spec)
+	}
+	return &Scheduler{cron: c}, nil
+}
+
+// Start inicia el planificador en segundo plano.
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop deja de disparar reportes y espera a los que estén en curso, hasta que ctx expire.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	select {
+	case <-s.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("reports still running at shutdown: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,142 @@
+// File: src/infrastructure/services/coalescing_notifier.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/metrics"
+)
+
+// CoalescingNotifier agrupa las notificaciones de un mismo pull request o commit (según
+// application.CoalesceKey) que llegan dentro de una ventana y las publica juntas, en los menos
+// mensajes que permiten los límites de Discord (application.CoalescePayloads).
+// Así un push seguido de una docena de ejecuciones de CI no llena el canal ni choca con los
+// límites de Discord. Implementa application.NotificationService delante de otro notificador.
+//
+// La entrega se da por procesada antes de publicar el lote, así que con agrupación el envío es
+// "como máximo una vez": si Discord rechaza el lote, ni el reenvío de GitHub ni la reproducción
+// desde el historial lo recuperan. Los lotes fallidos se cuentan en
+// metrics.CoalescedNotificationsTotal y se registran en el log.
+type CoalescingNotifier struct {
+	next   application.NotificationService
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingBatch // destino/hilo/clave → envíos retenidos
+	flushes sync.WaitGroup           // Lotes retenidos o en publicación
+}
+
+// pendingBatch son los envíos retenidos de una clave hasta que vence su ventana.
+type pendingBatch struct {
+	ctx         context.Context // Contexto del primer envío, sin su cancelación
+	destination string
+	payloads    []application.DiscordPayload
+	timer       *time.Timer
+}
+
+// NewCoalescingNotifier crea el agrupador delante de next. La ventana empieza con el primer envío de cada clave.
+func NewCoalescingNotifier(next application.NotificationService, window time.Duration) *CoalescingNotifier {
+	return &CoalescingNotifier{
+		next:    next,
+		window:  window,
+		pending: make(map[string]*pendingBatch),
+	}
+}
+
+// SendNotification implementa la interfaz application.NotificationService. Los envíos agrupables
+// se retienen y retornan enseguida sin mensaje: un fallo al publicar el lote no llega al llamador.
+// Los que no tienen clave, adjuntan archivos, abren un hilo o son críticos se publican directamente.
+func (n *CoalescingNotifier) SendNotification(ctx context.Context, channelType string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	key := application.CoalesceKey(ctx)
+	if key == "" || len(payload.Files) > 0 || payload.ThreadName != "" || payload.Critical {
+		return n.next.SendNotification(ctx, channelType, payload)
+	}
+	key = channelType + "/" + payload.ThreadID + "/" + key
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	metrics.CoalescingQueueDepth.WithLabelValues(channelType).Inc()
+	if batch, ok := n.pending[key]; ok {
+		batch.payloads = append(batch.payloads, payload)
+		application.Logger(ctx).Debug("Notification coalesced", "coalesce_key", key, "pending", len(batch.payloads))
+		return nil, nil
+	}
+	n.flushes.Add(1)
+	n.pending[key] = &pendingBatch{
+		ctx:         context.WithoutCancel(ctx),
+		destination: channelType,
+		payloads:    []application.DiscordPayload{payload},
+		timer:       time.AfterFunc(n.window, func() { _ = n.flush(key) }), // El fallo ya queda en el log
+	}
+	application.Logger(ctx).Debug("Notification buffered for coalescing", "coalesce_key", key, "window", n.window.String())
+	return nil, nil
+}
+
+// EditNotification implementa la interfaz application.NotificationService; las ediciones no se agrupan.
+func (n *CoalescingNotifier) EditNotification(ctx context.Context, channelType, messageID string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	return n.next.EditNotification(ctx, channelType, messageID, payload)
+}
+
+// Flush publica enseguida todos los lotes retenidos y espera los que ya se estaban publicando.
+// Se llama al apagar el servidor para no perder notificaciones; retorna los lotes que fallaron.
+func (n *CoalescingNotifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	var keys []string
+	for key, batch := range n.pending {
+		if batch.timer.Stop() {
+			keys = append(keys, key)
+		}
+	}
+	n.mu.Unlock()
+	var errs []error
+	for _, key := range keys {
+		if err := n.flush(key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		n.flushes.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// flush publica el lote de key en los menos mensajes que permiten los límites de Discord y
+// cuenta el resultado en las métricas. Un mensaje rechazado no impide publicar los demás.
+func (n *CoalescingNotifier) flush(key string) error {
+	n.mu.Lock()
+	batch, ok := n.pending[key]
+	delete(n.pending, key)
+	n.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	defer n.flushes.Done()
+	metrics.CoalescingQueueDepth.WithLabelValues(batch.destination).Sub(float64(len(batch.payloads)))
+
+	ctx := application.WithLogAttrs(batch.ctx, "coalesce_key", key, "coalesced", len(batch.payloads))
+	var errs []error
+	for _, message := range application.CoalescePayloads(batch.payloads) {
+		if _, err := n.next.SendNotification(ctx, batch.destination, message.Payload); err != nil {
+			metrics.CoalescedNotificationsTotal.WithLabelValues(batch.destination, metrics.BatchFailed).Add(float64(message.Count))
+			application.Logger(ctx).Error("Sending coalesced notification failed, its notifications are lost", "error", err, "notifications", message.Count)
+			errs = append(errs, fmt.Errorf("failed to send coalesced notification %s: %w", key, err))
+			continue
+		}
+		metrics.CoalescedNotificationsTotal.WithLabelValues(batch.destination, metrics.BatchSent).Add(float64(message.Count))
+		application.Logger(ctx).Info("Sent coalesced notification", "notifications", message.Count)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,204 @@
+// File: src/infrastructure/services/coalescing_notifier_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/metrics"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// recordingNotifier guarda los payloads que le llegan al notificador de atrás.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []application.DiscordPayload
+}
+
+func (n *recordingNotifier) SendNotification(_ context.Context, _ string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, payload)
+	return &application.DiscordMessage{ID: fmt.Sprintf("m%d", len(n.sent))}, nil
+}
+
+func (n *recordingNotifier) EditNotification(ctx context.Context, channelType, _ string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	return n.SendNotification(ctx, channelType, payload)
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.sent)
+}
+
+func TestCoalescingNotifierMergesBatch(t *testing.T) {
+	next := &recordingNotifier{}
+	notifier := NewCoalescingNotifier(next, time.Hour)
+	ctx := application.WithCoalesceKey(context.Background(), "octo-org/hello-world#42")
+
+	for i := range 12 {
+		payload := application.DiscordPayload{Embeds: []application.DiscordEmbed{{Title: fmt.Sprintf("run %d", i)}}}
+		if message, err := notifier.SendNotification(ctx, "testing", payload); err != nil || message != nil {
+			t.Fatalf("coalesced sends should return no message and no error, got %v, %v", message, err)
+		}
+	}
+	// Otro pull request va en su propio lote
+	other := application.WithCoalesceKey(context.Background(), "octo-org/hello-world#7")
+	notifier.SendNotification(other, "testing", samplePayload)
+	if next.count() != 0 {
+		t.Fatalf("nothing should be sent before the window ends, got %d", next.count())
+	}
+
+	if err := notifier.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 10 embeds por mensaje: el pull request #42 ocupa dos y el otro uno
+	var runs []string
+	for _, payload := range next.sent {
+		for _, embed := range payload.Embeds {
+			if embed.Title != samplePayload.Embeds[0].Title {
+				runs = append(runs, embed.Title)
+			}
+		}
+	}
+	if len(next.sent) != 3 || len(next.sent[0].Embeds)+len(next.sent[1].Embeds)+len(next.sent[2].Embeds) != 13 {
+		t.Fatalf("expected 13 embeds in 3 messages, got %d messages", len(next.sent))
+	}
+	if len(runs) != 12 || runs[0] != "run 0" || runs[11] != "run 11" {
+		t.Errorf("expected every run once and in order, got %v", runs)
+	}
+	for _, payload := range next.sent {
+		if len(payload.Embeds) > 10 {
+			t.Errorf("message with %d embeds", len(payload.Embeds))
+		}
+	}
+}
+
+func TestCoalescingNotifierEmbedBudget(t *testing.T) {
+	next := &recordingNotifier{}
+	notifier := NewCoalescingNotifier(next, time.Hour)
+	ctx := application.WithCoalesceKey(context.Background(), "octo-org/hello-world#42")
+
+	// Cuatro embeds de ~2500 caracteres: juntos superarían los 6000 que Discord acepta por mensaje
+	long := strings.Repeat("x", 2500)
+	for i := range 4 {
+		payload := application.DiscordPayload{
+			Content:         fmt.Sprintf("<@%d> run %d failed", 100+i, i),
+			AllowedMentions: &application.AllowedMentions{Users: []string{fmt.Sprint(100 + i)}},
+			Embeds:          []application.DiscordEmbed{{Title: fmt.Sprintf("run %d", i), Description: long}},
+		}
+		notifier.SendNotification(ctx, "testing", payload)
+	}
+	if err := notifier.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.sent) != 2 {
+		t.Fatalf("expected the batch split in 2 messages, got %d", len(next.sent))
+	}
+	for _, payload := range next.sent {
+		total := 0
+		for _, embed := range payload.Embeds {
+			total += len(embed.Title) + len(embed.Description)
+		}
+		if total > 6000 {
+			t.Errorf("message embeds total %d characters", total)
+		}
+		// El contenido se une por líneas: cada mención queda entera
+		for _, line := range strings.Split(payload.Content, "\n") {
+			if !strings.HasPrefix(line, "<@") || !strings.Contains(line, "> run ") {
+				t.Errorf("unexpected content line %q", line)
+			}
+		}
+	}
+}
+
+func TestCoalescingNotifierWindow(t *testing.T) {
+	next := &recordingNotifier{}
+	notifier := NewCoalescingNotifier(next, 20*time.Millisecond)
+	ctx := application.WithCoalesceKey(context.Background(), "octo-org/hello-world@6dcb09b")
+	notifier.SendNotification(ctx, "testing", samplePayload)
+	notifier.SendNotification(ctx, "testing", samplePayload)
+
+	deadline := time.Now().Add(time.Second)
+	for next.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if next.count() != 1 || len(next.sent[0].Embeds) != 2 {
+		t.Fatalf("expected one message with both embeds after the window, got %+v", next.sent)
+	}
+}
+
+func TestCoalescingNotifierBypass(t *testing.T) {
+	next := &recordingNotifier{}
+	notifier := NewCoalescingNotifier(next, time.Hour)
+	keyed := application.WithCoalesceKey(context.Background(), "octo-org/hello-world#42")
+
+	withFile := samplePayload
+	withFile.Files = []application.DiscordFile{{Name: "build.log", Content: []byte("boom")}}
+	opensThread := samplePayload
+	opensThread.ThreadName = "#42 Add retries"
+	critical := samplePayload
+	critical.Critical = true
+
+	notifier.SendNotification(context.Background(), "testing", samplePayload) // Sin clave
+	for _, payload := range []application.DiscordPayload{withFile, opensThread, critical} {
+		if message, err := notifier.SendNotification(keyed, "testing", payload); err != nil || message == nil {
+			t.Fatalf("expected a direct send returning the message, got %v, %v", message, err)
+		}
+	}
+	if _, err := notifier.EditNotification(keyed, "testing", "m1", samplePayload); err != nil {
+		t.Fatal(err)
+	}
+	if next.count() != 5 {
+		t.Errorf("expected every send and edit to pass through, got %d", next.count())
+	}
+}
+
+// failingNotifier rechaza todos los envíos, como Discord cuando el webhook ya no existe.
+type failingNotifier struct{}
+
+func (failingNotifier) SendNotification(context.Context, string, application.DiscordPayload) (*application.DiscordMessage, error) {
+	return nil, errors.New("discord returned 404")
+}
+
+func (failingNotifier) EditNotification(context.Context, string, string, application.DiscordPayload) (*application.DiscordMessage, error) {
+	return nil, errors.New("discord returned 404")
+}
+
+func TestCoalescingNotifierFailedFlush(t *testing.T) {
+	failedCount := func() float64 {
+		var metric dto.Metric
+		if err := metrics.CoalescedNotificationsTotal.WithLabelValues("deployment", metrics.BatchFailed).Write(&metric); err != nil {
+			t.Fatal(err)
+		}
+		return metric.GetCounter().GetValue()
+	}
+	before := failedCount()
+
+	notifier := NewCoalescingNotifier(failingNotifier{}, time.Hour)
+	ctx := application.WithCoalesceKey(context.Background(), "octo-org/hello-world#42")
+	for range 3 {
+		// El envío retenido no ve el fallo: la entrega se da por procesada
+		if _, err := notifier.SendNotification(ctx, "deployment", samplePayload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := notifier.Flush(context.Background()); err == nil {
+		t.Fatal("expected Flush to report the failed batch")
+	}
+	if got := failedCount() - before; got != 3 {
+		t.Errorf("expected the 3 lost notifications counted as failed, got %v", got)
+	}
+	if err := notifier.Flush(context.Background()); err != nil {
+		t.Errorf("the failed batch must not be retried, got %v", err)
+	}
+}
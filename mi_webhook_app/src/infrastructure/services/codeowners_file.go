@@ -0,0 +1,35 @@
+// File: src/infrastructure/services/codeowners_file.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"mi_webhook_app/src/application"
+	domain "mi_webhook_app/src/domain/value_objects"
+)
+
+// codeOwnersFile es un CODEOWNERS local que se aplica a todos los repositorios.
+type codeOwnersFile struct {
+	codeOwners *domain.CodeOwners
+}
+
+// LoadCodeOwnersFile lee el archivo en path y crea un adaptador implementando application.CodeOwnersSource.
+func LoadCodeOwnersFile(path string) (application.CodeOwnersSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CODEOWNERS file: %w", err)
+	}
+	defer file.Close()
+	codeOwners, err := domain.ParseCodeOwners(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &codeOwnersFile{codeOwners: codeOwners}, nil
+}
+
+// CodeOwners implementa application.CodeOwnersSource.
+func (f *codeOwnersFile) CodeOwners(context.Context, string) (*domain.CodeOwners, error) {
+	return f.codeOwners, nil
+}
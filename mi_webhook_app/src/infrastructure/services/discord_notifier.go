@@ -3,50 +3,108 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"net/url"
+	"time"
 
 	// --- IMPORTACIONES ACTUALIZADAS (usa tu nombre de módulo) ---
 	"mi_webhook_app/src/application"
 	"mi_webhook_app/src/infrastructure/config"
+	"mi_webhook_app/src/infrastructure/logging"
+	"mi_webhook_app/src/infrastructure/metrics"
 	// --- FIN IMPORTACIONES ACTUALIZADAS ---
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer crea un span por cada petición HTTP a Discord.
+var tracer = otel.Tracer("mi_webhook_app/services")
+
 // discordNotifier es la implementación concreta para enviar notificaciones a Discord.
 type discordNotifier struct {
 	config *config.AppConfig
+	client *http.Client
 }
 
 // NewDiscordNotifier crea un nuevo adaptador implementando application.NotificationService.
 func NewDiscordNotifier(cfg *config.AppConfig) application.NotificationService {
 	return &discordNotifier{
 		config: cfg,
+		// El límite de tiempo se aplica por destino a través del contexto de cada envío.
+		client: &http.Client{},
 	}
 }
 
 // SendNotification implementa la interfaz application.NotificationService.
-func (n *discordNotifier) SendNotification(channelType string, payload application.DiscordPayload) error {
-	webhookURL := n.getWebhookURL(channelType)
+// Publica con ?wait=true para que Discord retorne el mensaje creado (y el hilo, si se abrió uno).
+func (n *discordNotifier) SendNotification(ctx context.Context, channelType string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	return n.execute(ctx, "discord.send", http.MethodPost, channelType, "", payload)
+}
+
+// EditNotification implementa la interfaz application.NotificationService (PATCH /messages/{id}).
+func (n *discordNotifier) EditNotification(ctx context.Context, channelType, messageID string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	return n.execute(ctx, "discord.edit", http.MethodPatch, channelType, "/messages/"+url.PathEscape(messageID), payload)
+}
+
+// execute envía payload al webhook del canal (más path) y decodifica el mensaje que retorna Discord.
+func (n *discordNotifier) execute(ctx context.Context, spanName, method, channelType, path string, payload application.DiscordPayload) (*application.DiscordMessage, error) {
+	webhookURL, timeout := n.getDestination(channelType)
 	if webhookURL == "" {
 		// Es importante loguear pero también retornar error para que la app sepa que falló
-		log.Printf("ERROR: No Discord webhook URL configured for channel type: %s", channelType)
-		return fmt.Errorf("no webhook URL configured for channel type '%s'", channelType)
+		application.Logger(ctx).Error("No Discord webhook URL configured for channel type")
+		return nil, fmt.Errorf("no webhook URL configured for channel type '%s'", channelType)
+	}
+	webhookURL, err := webhookRequestURL(webhookURL, path, payload.ThreadID)
+	if err != nil {
+		application.Logger(ctx).Error("Parsing Discord webhook URL", "error", redactURLError(err))
+		return nil, fmt.Errorf("invalid webhook URL for channel type '%s'", channelType)
+	}
+
+	body, contentType, err := encodePayload(payload)
+	if err != nil {
+		application.Logger(ctx).Error("Marshalling Discord payload", "error", err)
+		return nil, fmt.Errorf("error marshalling discord payload: %w", err)
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	// Limita el envío al timeout del destino; la cancelación del llamador también aborta la petición.
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(application.AttrDestination.String(channelType)))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, method, webhookURL, body)
 	if err != nil {
-		log.Printf("ERROR: Marshalling Discord payload: %v", err)
-		return fmt.Errorf("error marshalling discord payload: %w", err)
+		err = redactURLError(err)
+		application.Logger(ctx).Error("Building Discord request", "error", err)
+		return nil, fmt.Errorf("error building http request for discord channel '%s': %w", channelType, err)
 	}
+	req.Header.Set("Content-Type", contentType)
 
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(payloadBytes))
+	start := time.Now()
+	resp, err := n.client.Do(req)
 	if err != nil {
-		log.Printf("ERROR: Sending message to Discord (%s): %v", webhookURL, err)
-		return fmt.Errorf("error sending http request to discord url %s: %w", webhookURL, err)
+		metrics.ObserveNotification(channelType, 0, time.Since(start))
+		err = redactURLError(err)
+		application.Logger(ctx).Error("Sending message to Discord", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "discord request failed")
+		return nil, fmt.Errorf("error sending http request to discord channel '%s': %w", channelType, err)
 	}
 	defer resp.Body.Close() // Siempre cierra el cuerpo
+	metrics.ObserveNotification(channelType, resp.StatusCode, time.Since(start))
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
 
 	// Verifica el código de estado de Discord
 	if resp.StatusCode >= 300 {
@@ -54,26 +112,111 @@ func (n *discordNotifier) SendNotification(channelType string, payload applicati
 		bodyBytes := new(bytes.Buffer)
 		_, readErr := bodyBytes.ReadFrom(resp.Body)
 		if readErr != nil {
-			log.Printf("ERROR: Reading Discord error response body: %v", readErr)
+			application.Logger(ctx).Error("Reading Discord error response body", "error", readErr)
+		}
+		application.Logger(ctx).Error("Discord webhook returned non-success status", "status", resp.Status, "body", bodyBytes.String())
+		span.SetStatus(codes.Error, resp.Status)
+		// Retorna un error que indica el fallo (sin la URL: contiene el token del webhook)
+		return nil, fmt.Errorf("discord webhook for channel '%s' failed with status %s", channelType, resp.Status)
+	}
+
+	// El envío ya fue aceptado: un cuerpo ilegible no lo convierte en fallo
+	var message application.DiscordMessage
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		application.Logger(ctx).Warn("Decoding Discord message response", "error", err)
+		return nil, nil
+	}
+	application.Logger(ctx).Info("Successfully sent notification to Discord", "message_id", message.ID)
+	return &message, nil // Éxito
+}
+
+// encodePayload serializa payload como JSON o, si lleva archivos, como multipart/form-data:
+// el mensaje va en la parte payload_json y cada archivo en files[n], declarado en attachments.
+func encodePayload(payload application.DiscordPayload) (*bytes.Buffer, string, error) {
+	if len(payload.Files) == 0 {
+		data, err := json.Marshal(payload)
+		return bytes.NewBuffer(data), "application/json", err
+	}
+
+	type attachment struct {
+		ID       int    `json:"id"`
+		Filename string `json:"filename"`
+	}
+	message := struct {
+		application.DiscordPayload
+		Attachments []attachment `json:"attachments"`
+	}{DiscordPayload: payload}
+	for i, file := range payload.Files {
+		message.Attachments = append(message.Attachments, attachment{ID: i, Filename: file.Name})
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, "", err
+	}
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+	for i, file := range payload.Files {
+		part, err := form.CreateFormFile(fmt.Sprintf("files[%d]", i), file.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(file.Content); err != nil {
+			return nil, "", err
 		}
-		log.Printf("ERROR: Discord webhook (%s) returned non-success status: %s. Body: %s", webhookURL, resp.Status, bodyBytes.String())
-		// Retorna un error que indica el fallo
-		return fmt.Errorf("discord webhook (%s) failed with status %s", webhookURL, resp.Status)
 	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return body, form.FormDataContentType(), nil
+}
 
-	log.Printf("INFO: Successfully sent notification to Discord channel type '%s'", channelType)
-	return nil // Éxito
+// webhookRequestURL añade path a la URL del webhook y los parámetros wait y, si corresponde, thread_id.
+func webhookRequestURL(webhookURL, path, threadID string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", err
+	}
+	u.Path += path
+	query := u.Query()
+	if path == "" {
+		query.Set("wait", "true") // Solo la publicación lo necesita; editar siempre retorna el mensaje
+	}
+	if threadID != "" {
+		query.Set("thread_id", threadID)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
 }
 
-// getWebhookURL recupera la URL apropiada basada en el tipo de canal lógico.
-func (n *discordNotifier) getWebhookURL(channelType string) string {
+// getDestination recupera la URL y el timeout apropiados basados en el tipo de canal lógico.
+func (n *discordNotifier) getDestination(channelType string) (string, time.Duration) {
 	switch channelType {
 	case "development":
-		return n.config.DiscordWebhookURLDevelopment
+		return n.config.DiscordWebhookURLDevelopment, n.config.DiscordTimeoutDevelopment
 	case "testing":
-		return n.config.DiscordWebhookURLTesting
+		return n.config.DiscordWebhookURLTesting, n.config.DiscordTimeoutTesting
 	default:
-		log.Printf("WARNING: Unknown channel type requested: %s", channelType)
-		return "" // Retorna vacío si el tipo no es conocido
+		slog.Warn("Unknown channel type requested", "destination", channelType)
+		return "", 0 // Retorna vacío si el tipo no es conocido
 	}
-}
\ No newline at end of file
+}
+
+// redactURLError oculta el token del webhook en errores de net/http, que incluyen la URL completa.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = logging.RedactURL(urlErr.URL)
+	}
+	return err
+}
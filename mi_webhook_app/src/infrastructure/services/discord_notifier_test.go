@@ -0,0 +1,232 @@
+// File: src/infrastructure/services/discord_notifier_test.go
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mi_webhook_app/src/application"
+	"mi_webhook_app/src/infrastructure/config"
+)
+
+// fakeDiscord levanta un stand-in de Discord que responde con handler y guarda el último cuerpo recibido.
+func fakeDiscord(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *[]byte) {
+	t.Helper()
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+// newTestNotifier apunta ambos canales al stand-in, con una URL que imita el token de un webhook real.
+func newTestNotifier(serverURL string, timeout time.Duration) application.NotificationService {
+	return NewDiscordNotifier(&config.AppConfig{
+		DiscordWebhookURLDevelopment: serverURL + "/api/webhooks/123/dev-secret-token",
+		DiscordWebhookURLTesting:     serverURL + "/api/webhooks/456/test-secret-token",
+		DiscordTimeoutDevelopment:    timeout,
+		DiscordTimeoutTesting:        timeout,
+	})
+}
+
+var samplePayload = application.DiscordPayload{Embeds: []application.DiscordEmbed{{Title: "hello", Color: 3447003}}}
+
+func TestSendNotificationSuccess(t *testing.T) {
+	server, body := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if r.URL.Path != "/api/webhooks/123/dev-secret-token" {
+			t.Errorf("posted to wrong webhook path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("wait") != "true" || r.URL.Query().Has("thread_id") {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"id": "1001", "channel_id": "2002"}`))
+	})
+
+	message, err := newTestNotifier(server.URL, time.Second).SendNotification(context.Background(), "development", samplePayload)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if message == nil || message.ID != "1001" || message.ChannelID != "2002" {
+		t.Errorf("unexpected message %+v", message)
+	}
+	var got application.DiscordPayload
+	if err := json.Unmarshal(*body, &got); err != nil || len(got.Embeds) != 1 || got.Embeds[0].Title != "hello" {
+		t.Fatalf("unexpected body posted to Discord: %s", *body)
+	}
+}
+
+func TestSendNotificationThread(t *testing.T) {
+	server, body := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("thread_id") != "777" {
+			t.Errorf("expected thread_id=777, got query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"id": "1002", "channel_id": "777"}`))
+	})
+
+	payload := samplePayload
+	payload.ThreadID = "777"
+	if _, err := newTestNotifier(server.URL, time.Second).SendNotification(context.Background(), "development", payload); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if strings.Contains(string(*body), "777") {
+		t.Errorf("thread ID must travel in the query, not the body: %s", *body)
+	}
+}
+
+func TestEditNotification(t *testing.T) {
+	server, _ := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/webhooks/456/test-secret-token/messages/1001" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("thread_id") != "777" || r.URL.Query().Has("wait") {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"id": "1001", "channel_id": "777"}`))
+	})
+
+	payload := samplePayload
+	payload.ThreadID = "777"
+	message, err := newTestNotifier(server.URL, time.Second).EditNotification(context.Background(), "testing", "1001", payload)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if message == nil || message.ID != "1001" {
+		t.Errorf("unexpected message %+v", message)
+	}
+}
+
+func TestSendNotificationFiles(t *testing.T) {
+	var contentType string
+	server, body := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		w.Write([]byte(`{"id": "1003", "channel_id": "2002"}`))
+	})
+
+	payload := samplePayload
+	payload.Files = []application.DiscordFile{{Name: "test.log", Content: []byte("FAIL\n")}}
+	if _, err := newTestNotifier(server.URL, time.Second).SendNotification(context.Background(), "development", payload); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("expected a multipart body, got content type %q", contentType)
+	}
+	form, err := multipart.NewReader(bytes.NewReader(*body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Embeds      []application.DiscordEmbed `json:"embeds"`
+		Attachments []struct {
+			ID       int    `json:"id"`
+			Filename string `json:"filename"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal([]byte(form.Value["payload_json"][0]), &got); err != nil || len(got.Embeds) != 1 {
+		t.Errorf("unexpected payload_json %q", form.Value["payload_json"])
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Filename != "test.log" {
+		t.Errorf("unexpected attachments %+v", got.Attachments)
+	}
+	files := form.File["files[0]"]
+	if len(files) != 1 || files[0].Filename != "test.log" {
+		t.Fatalf("missing files[0]: %+v", form.File)
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if content, _ := io.ReadAll(file); string(content) != "FAIL\n" {
+		t.Errorf("unexpected file content %q", content)
+	}
+}
+
+func TestSendNotificationErrorStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+	}{
+		{"bad request", http.StatusBadRequest},
+		{"not found", http.StatusNotFound},
+		{"rate limited", http.StatusTooManyRequests},
+		{"server error", http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server, _ := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+				if tc.status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "2")
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"message": "error"}`))
+			})
+
+			_, err := newTestNotifier(server.URL, time.Second).SendNotification(context.Background(), "testing", samplePayload)
+			if err == nil {
+				t.Fatalf("expected error for status %d", tc.status)
+			}
+			if !strings.Contains(err.Error(), http.StatusText(tc.status)) {
+				t.Errorf("error %q does not mention status %d", err, tc.status)
+			}
+			if strings.Contains(err.Error(), "secret-token") {
+				t.Errorf("error leaks the webhook token: %q", err)
+			}
+		})
+	}
+}
+
+func TestSendNotificationTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server, _ := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release // Simula una conexión colgada hasta que termina la prueba
+	})
+	defer close(release)
+
+	start := time.Now()
+	_, err := newTestNotifier(server.URL, 50*time.Millisecond).SendNotification(context.Background(), "development", samplePayload)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("timeout not honored, took %s", elapsed)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks the webhook token: %q", err)
+	}
+}
+
+func TestSendNotificationCancelledContext(t *testing.T) {
+	server, _ := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestNotifier(server.URL, time.Second).SendNotification(ctx, "development", samplePayload)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+}
+
+func TestSendNotificationUnknownChannel(t *testing.T) {
+	_, err := newTestNotifier("http://127.0.0.1:1", time.Second).SendNotification(context.Background(), "production", samplePayload)
+	if err == nil {
+		t.Fatal("expected error for unknown channel")
+	}
+}
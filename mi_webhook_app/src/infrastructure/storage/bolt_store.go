@@ -0,0 +1,61 @@
+// File: src/infrastructure/storage/bolt_store.go
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStore es el almacenamiento embebido de la aplicación (un archivo bbolt).
+// Cada puerto persistente se implementa sobre su propio bucket en este archivo.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore abre (o crea) la base de datos en path y prepara los buckets.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory %s: %w", dir, err)
+		}
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close cierra la base de datos.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// allBuckets lista los buckets que se crean al abrir la base de datos.
+var allBuckets = [][]byte{
+	bucketDeliveries,
+	bucketDeliveryIndex,
+	bucketThreads,
+	bucketStatusMessages,
+	bucketInstallations,
+	bucketRunResults,
+	bucketBranchStates,
+	bucketPullRequests,
+	bucketHeldNotifications,
+}
@@ -0,0 +1,117 @@
+// File: src/infrastructure/storage/bolt_store_test.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// discardNotifier acepta todos los envíos sin publicar nada.
+type discardNotifier struct{}
+
+func (discardNotifier) SendNotification(context.Context, string, application.DiscordPayload) (*application.DiscordMessage, error) {
+	return &application.DiscordMessage{ID: "1", ChannelID: "2"}, nil
+}
+
+func (discardNotifier) EditNotification(context.Context, string, string, application.DiscordPayload) (*application.DiscordMessage, error) {
+	return &application.DiscordMessage{ID: "1", ChannelID: "2"}, nil
+}
+
+// TestDryRunWritesNothing procesa cada fixture en dry-run con todas las funciones que guardan
+// estado habilitadas y verifica que el servicio no escribió en ningún bucket: el almacenamiento
+// guarda todo lo que recibe, así que la decisión es del servicio.
+func TestDryRunWritesNothing(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), "webhooks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	allDay := application.QuietHours{Start: 0, End: 24*time.Hour - time.Nanosecond}
+	service := application.NewWebhookService(discardNotifier{},
+		application.WithDeliveryStore(store, true),
+		application.WithPullRequestThreads(store, "development", "testing"),
+		application.WithStatusMessages(store),
+		application.WithInstallationStore(store),
+		application.WithFlakyDetection(store, 14*24*time.Hour, 20),
+		application.WithBranchHealth(store, true),
+		application.WithReviewReminders(store, application.ReviewReminderConfig{Threshold: time.Hour}),
+		application.WithQuietHours(store, map[string]application.QuietHours{"testing": allDay}))
+
+	fixtures, err := filepath.Glob("../../application/testdata/fixtures/*.json")
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures found: %v", err)
+	}
+	for _, path := range fixtures {
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := strings.Split(filepath.Base(path), ".")[0]
+		ctx := application.WithDryRun(context.Background())
+		event := application.Event{Name: name, DeliveryID: filepath.Base(path), Payload: payload}
+		if err := service.Process(ctx, event); err != nil && !errors.Is(err, application.ErrEventNotHandled) {
+			t.Errorf("%s: %v", filepath.Base(path), err)
+		}
+	}
+
+	err = store.db.View(func(tx *bolt.Tx) error {
+		for _, name := range allBuckets {
+			if n := tx.Bucket(name).Stats().KeyN; n != 0 {
+				t.Errorf("bucket %s has %d keys after dry-run deliveries", name, n)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestListDeliveriesCursor verifica que paginar con Until y BeforeID lee cada entrega una sola
+// vez, aunque varias se hayan recibido en el mismo instante.
+func TestListDeliveriesCursor(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), "webhooks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	start := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		received := start.Add(time.Duration(min(i, 3)) * time.Second) // d, e y f comparten instante
+		if err := store.SaveDelivery(ctx, application.DeliveryRecord{ID: id, ReceivedAt: received}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []string
+	filter := application.DeliveryFilter{Since: start, Limit: 2}
+	for {
+		page, err := store.ListDeliveries(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range page {
+			ids = append(ids, record.ID)
+		}
+		if len(page) < filter.Limit {
+			break
+		}
+		last := page[len(page)-1]
+		filter.Until, filter.BeforeID = last.ReceivedAt, last.ID
+	}
+	if got := strings.Join(ids, ","); got != "f,e,d,c,b,a" {
+		t.Errorf("expected every delivery once, newest first, got %s", got)
+	}
+}
@@ -0,0 +1,48 @@
+// File: src/infrastructure/storage/branch_state_store.go
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketBranchStates mapea repo/rama/workflow → application.BranchState.
+var bucketBranchStates = []byte("branch_states")
+
+// GetBranchState implementa application.BranchStateStore.
+func (s *BoltStore) GetBranchState(_ context.Context, key string) (application.BranchState, bool, error) {
+	var state application.BranchState
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketBranchStates).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &state)
+	})
+	if err != nil {
+		return application.BranchState{}, false, fmt.Errorf("failed to read branch state %s: %w", key, err)
+	}
+	return state, found, nil
+}
+
+// SaveBranchState implementa application.BranchStateStore.
+func (s *BoltStore) SaveBranchState(_ context.Context, key string, state application.BranchState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal branch state %s: %w", key, err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketBranchStates).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save branch state %s: %w", key, err)
+	}
+	return nil
+}
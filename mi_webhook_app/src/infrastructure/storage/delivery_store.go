@@ -0,0 +1,175 @@
+// File: src/infrastructure/storage/delivery_store.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// bucketDeliveries guarda los registros ordenados por fecha de recepción (clave: nanos + ID).
+	bucketDeliveries = []byte("deliveries")
+	// bucketDeliveryIndex mapea ID de entrega → clave en bucketDeliveries.
+	bucketDeliveryIndex = []byte("delivery_ids")
+)
+
+// defaultListLimit y maxListLimit acotan el tamaño de una página del historial.
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// deliveryKey ordena cronológicamente: 8 bytes big-endian de UnixNano seguidos del ID.
+func deliveryKey(record application.DeliveryRecord) []byte {
+	key := make([]byte, 8, 8+len(record.ID))
+	binary.BigEndian.PutUint64(key, uint64(record.ReceivedAt.UnixNano()))
+	return append(key, record.ID...)
+}
+
+// SaveDelivery implementa application.DeliveryStore. Reemplaza un registro previo con el mismo ID.
+func (s *BoltStore) SaveDelivery(_ context.Context, record application.DeliveryRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery %s: %w", record.ID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket(bucketDeliveries)
+		index := tx.Bucket(bucketDeliveryIndex)
+		if previous := index.Get([]byte(record.ID)); previous != nil {
+			if err := deliveries.Delete(previous); err != nil {
+				return err
+			}
+		}
+		key := deliveryKey(record)
+		if err := deliveries.Put(key, data); err != nil {
+			return err
+		}
+		return index.Put([]byte(record.ID), key)
+	})
+}
+
+// GetDelivery implementa application.DeliveryStore.
+func (s *BoltStore) GetDelivery(_ context.Context, id string) (*application.DeliveryRecord, error) {
+	var record application.DeliveryRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := tx.Bucket(bucketDeliveryIndex).Get([]byte(id))
+		if key == nil {
+			return application.ErrDeliveryNotFound
+		}
+		data := tx.Bucket(bucketDeliveries).Get(key)
+		if data == nil {
+			return application.ErrDeliveryNotFound
+		}
+		return json.Unmarshal(data, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListDeliveries implementa application.DeliveryStore recorriendo del más reciente al más antiguo.
+func (s *BoltStore) ListDeliveries(_ context.Context, filter application.DeliveryFilter) ([]application.DeliveryRecord, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	records := []application.DeliveryRecord{}
+	skipped := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(bucketDeliveries).Cursor()
+		var k, v []byte
+		if filter.Until.IsZero() {
+			k, v = cursor.Last()
+		} else {
+			// Posiciona en la primera clave >= Until (y BeforeID) y retrocede una: ambos son exclusivos
+			seek := deliveryKey(application.DeliveryRecord{ID: filter.BeforeID, ReceivedAt: filter.Until})
+			if k, _ = cursor.Seek(seek); k == nil {
+				k, v = cursor.Last()
+			} else {
+				k, v = cursor.Prev()
+			}
+		}
+		for ; k != nil; k, v = cursor.Prev() {
+			if !filter.Since.IsZero() && int64(binary.BigEndian.Uint64(k[:8])) < filter.Since.UnixNano() {
+				break // Las claves restantes son más antiguas
+			}
+			var record application.DeliveryRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("failed to unmarshal delivery %x: %w", k, err)
+			}
+			if !matchesFilter(record, filter) {
+				continue
+			}
+			if skipped < filter.Offset {
+				skipped++
+				continue
+			}
+			records = append(records, record)
+			if len(records) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return records, err
+}
+
+// PruneDeliveries implementa application.DeliveryStore. Las claves están ordenadas por fecha de
+// recepción, así que solo se recorren las entregas vencidas.
+func (s *BoltStore) PruneDeliveries(_ context.Context, before time.Time) (int, error) {
+	var pruned int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket(bucketDeliveries)
+		index := tx.Bucket(bucketDeliveryIndex)
+		// Las claves se juntan antes de borrar: borrar mientras se recorre salta claves
+		var expired [][]byte
+		cursor := deliveries.Cursor()
+		for k, _ := cursor.First(); k != nil && int64(binary.BigEndian.Uint64(k[:8])) < before.UnixNano(); k, _ = cursor.Next() {
+			expired = append(expired, bytes.Clone(k))
+		}
+		for _, k := range expired {
+			// El índice solo se borra si todavía apunta a esta clave (el ID pudo guardarse de nuevo)
+			if id := k[8:]; bytes.Equal(index.Get(id), k) {
+				if err := index.Delete(id); err != nil {
+					return err
+				}
+			}
+			if err := deliveries.Delete(k); err != nil {
+				return err
+			}
+		}
+		pruned = len(expired)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune deliveries: %w", err)
+	}
+	return pruned, nil
+}
+
+// matchesFilter aplica los filtros que no dependen del orden de las claves.
+func matchesFilter(record application.DeliveryRecord, filter application.DeliveryFilter) bool {
+	if filter.Repo != "" && record.Repo != filter.Repo {
+		return false
+	}
+	if filter.Event != "" && record.Event != filter.Event {
+		return false
+	}
+	if filter.Outcome != "" && record.Outcome != filter.Outcome {
+		return false
+	}
+	return true
+}
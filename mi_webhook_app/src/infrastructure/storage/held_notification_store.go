@@ -0,0 +1,90 @@
+// File: src/infrastructure/storage/held_notification_store.go
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketHeldNotifications mapea secuencia (big-endian, en orden de llegada) → application.HeldNotification.
+var bucketHeldNotifications = []byte("held_notifications")
+
+func heldKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// HoldNotification implementa application.HeldNotificationStore. Ignora held.ID y asigna uno nuevo.
+func (s *BoltStore) HoldNotification(_ context.Context, held application.HeldNotification) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketHeldNotifications)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		held.ID = id
+		data, err := json.Marshal(held)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(heldKey(id), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hold notification for %s: %w", held.Destination, err)
+	}
+	return nil
+}
+
+// ListHeldNotifications implementa application.HeldNotificationStore.
+func (s *BoltStore) ListHeldNotifications(_ context.Context) ([]application.HeldNotification, error) {
+	var held []application.HeldNotification
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketHeldNotifications).ForEach(func(k, v []byte) error {
+			var notification application.HeldNotification
+			if err := json.Unmarshal(v, &notification); err != nil {
+				return fmt.Errorf("failed to decode held notification %x: %w", k, err)
+			}
+			held = append(held, notification)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list held notifications: %w", err)
+	}
+	return held, nil
+}
+
+// UpdateHeldNotification implementa application.HeldNotificationStore. No hace nada si ya no existe.
+func (s *BoltStore) UpdateHeldNotification(_ context.Context, held application.HeldNotification) error {
+	data, err := json.Marshal(held)
+	if err != nil {
+		return fmt.Errorf("failed to marshal held notification %d: %w", held.ID, err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketHeldNotifications)
+		if bucket.Get(heldKey(held.ID)) == nil {
+			return nil // Ya se publicó o se borró
+		}
+		return bucket.Put(heldKey(held.ID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update held notification %d: %w", held.ID, err)
+	}
+	return nil
+}
+
+// DeleteHeldNotification implementa application.HeldNotificationStore.
+func (s *BoltStore) DeleteHeldNotification(_ context.Context, id uint64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketHeldNotifications).Delete(heldKey(id))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete held notification %d: %w", id, err)
+	}
+	return nil
+}
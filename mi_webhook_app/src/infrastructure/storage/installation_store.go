@@ -0,0 +1,85 @@
+// File: src/infrastructure/storage/installation_store.go
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketInstallations mapea el ID de una instalación de la GitHub App → application.Installation.
+var bucketInstallations = []byte("installations")
+
+func installationKey(id int64) []byte {
+	return []byte(strconv.FormatInt(id, 10))
+}
+
+// SaveInstallation implementa application.InstallationStore.
+func (s *BoltStore) SaveInstallation(_ context.Context, installation application.Installation) error {
+	data, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installation %d: %w", installation.ID, err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketInstallations).Put(installationKey(installation.ID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save installation %d: %w", installation.ID, err)
+	}
+	return nil
+}
+
+// DeleteInstallation implementa application.InstallationStore.
+func (s *BoltStore) DeleteInstallation(_ context.Context, id int64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketInstallations).Delete(installationKey(id))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete installation %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetInstallation implementa application.InstallationStore.
+func (s *BoltStore) GetInstallation(_ context.Context, id int64) (*application.Installation, bool, error) {
+	var installation *application.Installation
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketInstallations).Get(installationKey(id))
+		if data == nil {
+			return nil
+		}
+		installation = &application.Installation{}
+		return json.Unmarshal(data, installation)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read installation %d: %w", id, err)
+	}
+	return installation, installation != nil, nil
+}
+
+// FindInstallation implementa application.InstallationStore. Recorre todas las instalaciones:
+// una GitHub App rara vez tiene más de unas decenas.
+func (s *BoltStore) FindInstallation(_ context.Context, repo string) (*application.Installation, bool, error) {
+	var match *application.Installation
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketInstallations).ForEach(func(_, data []byte) error {
+			var installation application.Installation
+			if err := json.Unmarshal(data, &installation); err != nil {
+				return err
+			}
+			if match == nil && installation.Covers(repo) {
+				match = &installation
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to search installations for %s: %w", repo, err)
+	}
+	return match, match != nil, nil
+}
@@ -0,0 +1,78 @@
+// File: src/infrastructure/storage/pull_request_store.go
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketPullRequests mapea repo#número → application.TrackedPullRequest de los pull requests abiertos.
+var bucketPullRequests = []byte("pull_requests")
+
+// GetPullRequest implementa application.PullRequestStore.
+func (s *BoltStore) GetPullRequest(_ context.Context, key string) (application.TrackedPullRequest, bool, error) {
+	var pr application.TrackedPullRequest
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketPullRequests).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &pr)
+	})
+	if err != nil {
+		return application.TrackedPullRequest{}, false, fmt.Errorf("failed to read pull request %s: %w", key, err)
+	}
+	return pr, found, nil
+}
+
+// SavePullRequest implementa application.PullRequestStore.
+func (s *BoltStore) SavePullRequest(_ context.Context, key string, pr application.TrackedPullRequest) error {
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pull request %s: %w", key, err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketPullRequests).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save pull request %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeletePullRequest implementa application.PullRequestStore. Borrar uno que no existe no es un error.
+func (s *BoltStore) DeletePullRequest(_ context.Context, key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketPullRequests).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete pull request %s: %w", key, err)
+	}
+	return nil
+}
+
+// ListPullRequests implementa application.PullRequestStore.
+func (s *BoltStore) ListPullRequests(_ context.Context) ([]application.TrackedPullRequest, error) {
+	var prs []application.TrackedPullRequest
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketPullRequests).ForEach(func(k, v []byte) error {
+			var pr application.TrackedPullRequest
+			if err := json.Unmarshal(v, &pr); err != nil {
+				return fmt.Errorf("failed to decode pull request %s: %w", k, err)
+			}
+			prs = append(prs, pr)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	return prs, nil
+}
@@ -0,0 +1,102 @@
+// File: src/infrastructure/storage/run_history_store.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketRunResults mapea repo/workflow/run/intento → application.RunResult.
+// La clave empieza por el repositorio y el workflow para consultarlos por prefijo.
+var bucketRunResults = []byte("run_results")
+
+func runResultPrefix(repo string, workflowID int64) []byte {
+	if repo == "" {
+		return nil
+	}
+	if workflowID == 0 {
+		return []byte(repo + "/")
+	}
+	return []byte(fmt.Sprintf("%s/%d/", repo, workflowID))
+}
+
+// SaveRunResult implementa application.RunHistoryStore. Un intento repetido (ej: una
+// entrega reproducida) sobreescribe al anterior.
+func (s *BoltStore) SaveRunResult(_ context.Context, result application.RunResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run result %d: %w", result.RunID, err)
+	}
+	key := fmt.Sprintf("%s%d/%d", runResultPrefix(result.Repo, result.WorkflowID), result.RunID, result.Attempt)
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRunResults).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save run result %d: %w", result.RunID, err)
+	}
+	return nil
+}
+
+// ListRunResults implementa application.RunHistoryStore.
+func (s *BoltStore) ListRunResults(_ context.Context, filter application.RunFilter) ([]application.RunResult, error) {
+	prefix := runResultPrefix(filter.Repo, filter.WorkflowID)
+	var results []application.RunResult
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketRunResults).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var result application.RunResult
+			if err := json.Unmarshal(v, &result); err != nil {
+				return fmt.Errorf("failed to decode run result %s: %w", k, err)
+			}
+			if result.CompletedAt.Before(filter.Since) {
+				continue
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run results: %w", err)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].CompletedAt.Before(results[j].CompletedAt) })
+	return results, nil
+}
+
+// PruneRunResults implementa application.RunHistoryStore.
+func (s *BoltStore) PruneRunResults(_ context.Context, before time.Time) (int, error) {
+	var pruned int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketRunResults)
+		var expired [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+			var result application.RunResult
+			// Los resultados ilegibles también se descartan
+			if err := json.Unmarshal(v, &result); err != nil || result.CompletedAt.Before(before) {
+				expired = append(expired, bytes.Clone(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		pruned = len(expired)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune run results: %w", err)
+	}
+	return pruned, nil
+}
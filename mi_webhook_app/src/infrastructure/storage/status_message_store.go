@@ -0,0 +1,59 @@
+// File: src/infrastructure/storage/status_message_store.go
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mi_webhook_app/src/application"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketStatusMessages mapea la clave de un estado editable (ej: destino/repo/runs/id/intento) → mensaje.
+var bucketStatusMessages = []byte("status_messages")
+
+// GetStatusMessage implementa application.StatusMessageStore.
+func (s *BoltStore) GetStatusMessage(_ context.Context, key string) (application.StatusMessage, bool, error) {
+	var message application.StatusMessage
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketStatusMessages).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &message)
+	})
+	if err != nil {
+		return application.StatusMessage{}, false, fmt.Errorf("failed to read status message %s: %w", key, err)
+	}
+	return message, found, nil
+}
+
+// SaveStatusMessage implementa application.StatusMessageStore.
+func (s *BoltStore) SaveStatusMessage(_ context.Context, key string, message application.StatusMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status message %s: %w", key, err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStatusMessages).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save status message %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteStatusMessage implementa application.StatusMessageStore.
+func (s *BoltStore) DeleteStatusMessage(_ context.Context, key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStatusMessages).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete status message %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// File: src/infrastructure/storage/thread_store.go
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketThreads mapea la clave de un pull request (destino/repo#número) → ID del hilo de Discord.
+var bucketThreads = []byte("threads")
+
+// GetThread implementa application.ThreadStore.
+func (s *BoltStore) GetThread(_ context.Context, key string) (string, bool, error) {
+	var threadID string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		threadID = string(tx.Bucket(bucketThreads).Get([]byte(key)))
+		return nil
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read thread %s: %w", key, err)
+	}
+	return threadID, threadID != "", nil
+}
+
+// SaveThread implementa application.ThreadStore.
+func (s *BoltStore) SaveThread(_ context.Context, key, threadID string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketThreads).Put([]byte(key), []byte(threadID))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save thread %s: %w", key, err)
+	}
+	return nil
+}
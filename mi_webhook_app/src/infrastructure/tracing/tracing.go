@@ -0,0 +1,59 @@
+// File: src/infrastructure/tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// serviceName identifica a este servicio en el backend de trazas.
+const serviceName = "mi_webhook_app"
+
+// Exportadores soportados (OTEL_TRACES_EXPORTER).
+const (
+	ExporterNone   = "none"   // Sin exportar (el tracer global es no-op)
+	ExporterOTLP   = "otlp"   // OTLP/HTTP; el endpoint se toma de OTEL_EXPORTER_OTLP_ENDPOINT
+	ExporterStdout = "stdout" // Imprime los spans en stdout para depuración local
+)
+
+// Setup instala el TracerProvider global según el exportador elegido.
+// Retorna una función que vacía y cierra el exportador; debe llamarse al apagar.
+func Setup(ctx context.Context, exporterName string) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q (use otlp, stdout or none)", exporterName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exporterName, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}